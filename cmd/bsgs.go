@@ -0,0 +1,135 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/bsgs"
+	"GoKeyHunt/internal/collision"
+	"GoKeyHunt/internal/console"
+	"GoKeyHunt/internal/utils"
+	"fmt"
+	"log"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// runBSGS searches the key of the target wallet with the baby-step giant-step method.
+// The baby-step table is sized to the memory budget, capped at what the range can use, and saved in data/ so later
+// runs load it instead of building it again. Giant steps are handed to the workers in units of GroupSize steps;
+// every finished unit is recorded in the progress intervals, and units already covered are skipped. When the search
+// is stopped, no further unit is handed out and the units in flight are finished and recorded.
+//
+// Parameters:
+// - ctx: The application context containing configuration parameters, wallet ranges, wallets, intervals, and results.
+func runBSGS(ctx *app_context.AppCtx) {
+	params := *ctx.Params
+	rawPublicKey, publicKey := targetPublicKey(params, *ctx.Wallets)
+	start, end := utils.GetWalletStartAndEnd(*ctx.WalletRanges, params)
+
+	// A giant step covers 2m+1 keys, so more than half the range worth of baby steps is never used.
+	size := bsgs.TableSize(params.MemoryBudget)
+	half := new(big.Int).Sub(end, start)
+	half.Rsh(half, 1).Add(half, big.NewInt(1))
+	if half.IsUint64() && half.Uint64() < size {
+		size = half.Uint64()
+	}
+	if size == 0 {
+		log.Fatalf("\nError: The memory budget is too small for a baby-step table.")
+	}
+
+	tablePath := filepath.Join(utils.GetRootDir(), "data", fmt.Sprintf("bsgs-%d.tbl", size))
+	table, built, err := bsgs.LoadOrBuildTable(tablePath, size, params.WorkerCount)
+	if err != nil {
+		log.Println("Error on save baby-step table:", err)
+	}
+	solver, err := bsgs.NewSolver(table, publicKey, start, end)
+	if err != nil {
+		log.Fatalf("\nError: %v", err)
+	}
+
+	if params.VerboseSummary {
+		console.PrintBSGSSummary(start, end, params, size, built, solver.GiantSteps())
+	}
+
+	var mu sync.Mutex
+	covered := new(big.Int)
+	units := make(chan [2]uint64, params.WorkerCount*2)
+	var workerGroup sync.WaitGroup
+	workerGroup.Add(params.WorkerCount)
+	for i := 0; i < params.WorkerCount; i++ {
+		go func() {
+			defer workerGroup.Done()
+			for unit := range units {
+				if solver.Search(unit[0], unit[1], params.GroupSize) != nil {
+					continue
+				}
+				a, b := solver.Covered(unit[0], unit[1])
+				if err := ctx.Journal.Record(new(collision.Interval).Set(a, b)); err != nil {
+					log.Println("Error on write journal:", err)
+				}
+				mu.Lock()
+				ctx.Intervals.Append(new(collision.Interval).Set(a, b))
+				covered.Add(covered, b).Sub(covered, a).Add(covered, big.NewInt(1))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	if params.VerboseProgress {
+		go printBSGSProgress(start, end, covered, &mu, done, params.UpdateInterval)
+	}
+
+	unitSteps := uint64(params.GroupSize)
+	for first := uint64(0); solver.Found() == nil && !stopped(ctx.Stop); first += unitSteps {
+		last := first + min(unitSteps, solver.GiantSteps()-first) - 1
+		a, b := solver.Covered(first, last)
+		mu.Lock()
+		skip := ctx.Intervals.Covers(new(collision.Interval).Set(a, b))
+		mu.Unlock()
+		if !skip {
+			select {
+			case units <- [2]uint64{first, last}:
+			case <-ctx.Stop:
+			}
+		}
+		if last == solver.GiantSteps()-1 {
+			break
+		}
+	}
+	close(units)
+	workerGroup.Wait()
+	close(done)
+
+	if key := solver.Found(); key != nil {
+		saveKeyResult(ctx, key, rawPublicKey)
+	}
+}
+
+// printBSGSProgress prints the keys covered by the giant steps of this run every updateInterval seconds until
+// done is closed.
+//
+// Parameters:
+// - start: The smallest key of the range.
+// - end: The largest key of the range.
+// - covered: The number of keys covered so far, guarded by mu.
+// - mu: The mutex guarding covered.
+// - done: A channel closed when the search ends.
+// - updateInterval: The interval between updates in seconds.
+func printBSGSProgress(start, end, covered *big.Int, mu *sync.Mutex, done <-chan struct{}, updateInterval int) {
+	startTime := time.Now()
+	ticker := time.NewTicker(time.Duration(updateInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			mu.Lock()
+			current := new(big.Int).Add(start, covered)
+			mu.Unlock()
+			console.PrintProgressString(start, end, current, startTime, time.Time{})
+		}
+	}
+}
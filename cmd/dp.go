@@ -0,0 +1,93 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/domain"
+	"GoKeyHunt/internal/kangaroo"
+	"GoKeyHunt/internal/output_results"
+	"GoKeyHunt/internal/utils"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+const dpUsage = "Usage: GoKeyHunt dp merge [-o merged.dp] node1.dp node2.dp ..."
+
+// runDPCommand runs the dp subcommand, which works on distinguished point files written by the kangaroo mode.
+// "dp merge" combines the files of several nodes, reports a key revealed by a tame and a wild point of different
+// nodes and records it in results.json, and optionally writes the combined points to a new file that every node
+// can resume from.
+//
+// Parameters:
+// - args: The command-line arguments following "dp".
+func runDPCommand(args []string) {
+	if len(args) == 0 || args[0] != "merge" {
+		fmt.Println(dpUsage)
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet("dp merge", flag.ExitOnError)
+	output := flags.String("o", "", "Merged distinguished point file to create. If empty, the files are only searched for a collision.")
+	flags.Usage = func() {
+		fmt.Println(dpUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args[1:])
+	if flags.NArg() == 0 {
+		flags.Usage()
+		log.Fatalf("\nError: No distinguished point files to merge.")
+	}
+
+	result, err := kangaroo.Merge(*output, flags.Args())
+	if err != nil {
+		log.Fatalf("\nError: %v", err)
+	}
+	fmt.Printf("- Files merged: %d\n", flags.NArg())
+	fmt.Printf("- Distinguished points: %s\n", humanize.Comma(int64(result.Points)))
+	fmt.Printf("- Duplicate points: %s\n", humanize.Comma(int64(result.Duplicates)))
+	if *output != "" {
+		fmt.Printf("- Written to: %s\n", *output)
+	}
+	if result.Key == nil {
+		fmt.Println("- No collision between tame and wild kangaroos yet.")
+		return
+	}
+
+	ranges, wallets, _ := utils.LoadData()
+	targetWallet := findPuzzleWallet(*ranges, result.Header)
+	if targetWallet == 0 {
+		fmt.Println("Warning: no range of ranges.json matches the files; the key is recorded for wallet 0.")
+	}
+	resultPathFile := filepath.Join(utils.GetRootDir(), "results.json")
+	ctx := &app_context.AppCtx{
+		Params:         &domain.Parameters{TargetWallet: targetWallet, SearchMode: domain.KangarooMode, VerboseKeyFind: true},
+		Wallets:        wallets,
+		Results:        output_results.ReadOrNew(resultPathFile),
+		ResultPathFile: resultPathFile,
+	}
+	saveKeyResult(ctx, result.Key, result.Header.Target[:])
+}
+
+// findPuzzleWallet returns the wallet whose range in ranges.json is the range of a distinguished point file.
+//
+// Parameters:
+// - ranges: The wallet ranges.
+// - header: The header of the distinguished point file.
+//
+// Returns:
+// - int: The wallet number, or 0 if no range matches.
+func findPuzzleWallet(ranges domain.Ranges, header kangaroo.DPHeader) int {
+	for i := 1; i < len(ranges.Ranges); i++ {
+		min, okMin := new(big.Int).SetString(strings.TrimPrefix(ranges.Ranges[i].Min, "0x"), 16)
+		max, okMax := new(big.Int).SetString(strings.TrimPrefix(ranges.Ranges[i].Max, "0x"), 16)
+		if okMin && okMax && min.Cmp(header.Min) == 0 && max.Cmp(header.Max) == 0 {
+			return i
+		}
+	}
+	return 0
+}
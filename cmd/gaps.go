@@ -0,0 +1,36 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/collision"
+	"GoKeyHunt/internal/console"
+	"GoKeyHunt/internal/core"
+	"GoKeyHunt/internal/utils"
+)
+
+// scheduleGaps schedules every batch inside the largest gap of the wallet range that the progress does not cover
+// yet, in the center of the gap or at a seeded random offset. Batches never overlap the searched keys, so the
+// progress grows without the slivers a random start and a collision nudge leave behind, and the gaps it leaves are
+// split evenly when the batches are centered.
+//
+// Parameters:
+// - ctx: The application context containing configuration parameters, wallet ranges and intervals.
+// - inputChannel: The channel work units are sent to.
+func scheduleGaps(ctx *app_context.AppCtx, inputChannel chan core.WorkUnit) {
+	params, intervals := *ctx.Params, ctx.Intervals
+	start, end := utils.GetWalletStartAndEnd(*ctx.WalletRanges, params)
+
+	for i := 0; (i < params.BatchCount || params.BatchCount == -1) && !stopped(ctx.Stop); i++ {
+		gaps := intervals.Gaps(start, end, 1)
+		if len(gaps) == 0 {
+			break
+		}
+		batchStart, batchEnd := utils.PlaceInGap(gaps[0], params, i+1)
+		console.PrintSummaryIfVerbose(start, batchStart, end, params, i+1)
+
+		checked := core.Schedule(batchStart, batchEnd, params, inputChannel, scheduleHooks(ctx, batchStart))
+		if checked.Cmp(batchStart) >= 0 {
+			intervals.Append(new(collision.Interval).Set(batchStart, checked))
+		}
+	}
+}
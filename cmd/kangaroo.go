@@ -0,0 +1,79 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/console"
+	"GoKeyHunt/internal/kangaroo"
+	"GoKeyHunt/internal/utils"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+// runKangaroo searches the key of the target wallet with Pollard's kangaroo method.
+// It takes the range of the target wallet from ranges.json and the target public key from the -pk flag or, if the
+// flag is empty, from the target wallet itself. Distinguished points are saved next to the progress file, so an
+// interrupted search resumes from the points already found.
+//
+// Parameters:
+// - ctx: The application context containing configuration parameters, wallet ranges, wallets, and results.
+func runKangaroo(ctx *app_context.AppCtx) {
+	params := *ctx.Params
+	rawPublicKey, publicKey := targetPublicKey(params, *ctx.Wallets)
+
+	start, end := utils.GetWalletStartAndEnd(*ctx.WalletRanges, params)
+	dpPath := filepath.Join(utils.GetRootDir(), "data", fmt.Sprintf("wallet-%d-kangaroo.dp", params.TargetWallet))
+	solver, err := kangaroo.NewSolver(kangaroo.Config{
+		Min:      start,
+		Max:      end,
+		Target:   publicKey,
+		Workers:  params.WorkerCount,
+		HerdSize: max(params.GroupSize, 2),
+		DPBits:   -1,
+		DPPath:   dpPath,
+	})
+	if err != nil {
+		log.Fatalf("\nError: %v", err)
+	}
+
+	if params.VerboseSummary {
+		console.PrintKangarooSummary(start, end, params, solver.DPBits(), solver.DistinguishedPoints(), solver.ExpectedJumps())
+	}
+
+	done := make(chan struct{})
+	if params.VerboseProgress {
+		go printKangarooProgress(solver, done, params.UpdateInterval)
+	}
+	key, err := solver.Run(ctx.Stop)
+	close(done)
+
+	if key != nil {
+		saveKeyResult(ctx, key, rawPublicKey)
+	}
+	if err != nil {
+		// The points that could not be written are lost for a later resume, so the run fails once the rest is saved.
+		closeProgress(ctx)
+		log.Fatalf("\nError: Could not write distinguished points to %s: %v", dpPath, err)
+	}
+}
+
+// printKangarooProgress prints the progress of a kangaroo search every updateInterval seconds until done is closed.
+//
+// Parameters:
+// - solver: The running kangaroo solver.
+// - done: A channel closed when the search ends.
+// - updateInterval: The interval between updates in seconds.
+func printKangarooProgress(solver *kangaroo.Solver, done <-chan struct{}, updateInterval int) {
+	startTime := time.Now()
+	ticker := time.NewTicker(time.Duration(updateInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			console.PrintKangarooProgress(solver.Jumps(), solver.ExpectedJumps(), solver.DistinguishedPoints(), startTime)
+		}
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"GoKeyHunt/internal/collision"
 	"GoKeyHunt/internal/console"
 	"GoKeyHunt/internal/core"
+	"GoKeyHunt/internal/domain"
 	"GoKeyHunt/internal/output_results"
 	"GoKeyHunt/internal/utils"
 	"fmt"
 	"math/big"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
@@ -20,16 +22,30 @@ const version = "GoKeyHunt 1.0.0 | Created by Lucas Kalil"
 // starts the main application logic, and prints a summary of the execution.
 func main() {
 	fmt.Println(version)
+	if len(os.Args) > 1 && os.Args[1] == "dp" {
+		runDPCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "progress" {
+		runProgressCommand(os.Args[2:])
+		return
+	}
 	ctx := createAppContext()
+	defer closeStore(ctx.Intervals)
 	startTime := time.Now()
 
-	runApplication(ctx)
+	switch ctx.Params.SearchMode {
+	case domain.KangarooMode:
+		runKangaroo(ctx)
+	case domain.BSGSMode:
+		runBSGS(ctx)
+	default:
+		runApplication(ctx)
+	}
 
-	sizeBeforeOp := ctx.Intervals.Size()
-	ctx.Intervals.Save(ctx.CollisionPathFile)
-	sizeAfterOp := ctx.Intervals.Size()
+	closeProgress(ctx)
 
-	console.PrintEndSummaryIfVerbose(ctx, startTime, sizeBeforeOp, sizeAfterOp)
+	console.PrintEndSummaryIfVerbose(ctx, startTime)
 }
 
 // runApplication orchestrates the execution of the application logic.
@@ -42,8 +58,8 @@ func runApplication(ctx *app_context.AppCtx) {
 	params, ranges, wallets, resultsJsonPath := *ctx.Params, *ctx.WalletRanges, *ctx.Wallets, ctx.ResultPathFile
 	intervals, results := ctx.Intervals, ctx.Results
 
-	inputChannel := make(chan *big.Int, params.WorkerCount*2)
-	outputChannel := make(chan *big.Int, params.WorkerCount)
+	inputChannel := make(chan core.WorkUnit, params.WorkerCount*2)
+	outputChannel := make(chan domain.Match, params.WorkerCount)
 	var workerGroup, outputGroup sync.WaitGroup
 
 	workerGroup.Add(1)
@@ -51,23 +67,45 @@ func runApplication(ctx *app_context.AppCtx) {
 	go core.WorkersStartUp(params, wallets, inputChannel, outputChannel, &workerGroup)
 	go output_results.OutputHandler(params, wallets, results, resultsJsonPath, outputChannel, &outputGroup)
 
-	for i := 0; i < params.BatchCount || params.BatchCount == -1; i++ {
+	if params.Rng || params.GapMode == domain.RandomGap {
 		start, end := utils.GetWalletStartAndEnd(ranges, params)
-		startOriginal := utils.Clone(start)
-
-		start = utils.GetStart(startOriginal, end, params, i+1)
-
-		if start.Cmp(end) > 0 {
-			break
-		}
-
-		console.PrintSummaryIfVerbose(startOriginal, start, end, params, i+1)
+		runLog := filepath.Join(utils.GetRootDir(), "data", fmt.Sprintf("wallet-%d-runs.log", params.TargetWallet))
+		utils.AppendRunLog(runLog, utils.NewRunRecord(start, end, params))
+	}
 
-		hasCollision, newInterval := utils.HandleCollisions(startOriginal, start, end, params, intervals)
-		if !hasCollision {
-			start, end = newInterval.Get()
-			core.Scheduler(start, end, params, inputChannel)
-			intervals.Append(new(collision.Interval).Set(start, end))
+	if params.Permutation {
+		schedulePermutation(ctx, inputChannel)
+	} else if params.GapMode != domain.NoGap {
+		scheduleGaps(ctx, inputChannel)
+	} else {
+		// Sequential batches start at the first key the progress does not cover yet and move forward from there.
+		next, _ := utils.GetWalletStartAndEnd(ranges, params)
+		for i := 0; (i < params.BatchCount || params.BatchCount == -1) && !stopped(ctx.Stop); i++ {
+			start, end := utils.GetWalletStartAndEnd(ranges, params)
+			startOriginal := utils.Clone(start)
+
+			if params.Rng {
+				start = utils.GetStart(startOriginal, end, params, i+1)
+			} else {
+				start = intervals.FirstUncovered(next)
+			}
+
+			if start.Cmp(end) > 0 {
+				break
+			}
+
+			console.PrintSummaryIfVerbose(startOriginal, start, end, params, i+1)
+
+			hasCollision, newInterval := utils.HandleCollisions(startOriginal, start, end, params, intervals)
+			next = new(big.Int).Add(utils.GetEnd(start, end, params), big.NewInt(1))
+			if !hasCollision {
+				start, end = newInterval.Get()
+				checked := core.Schedule(start, end, params, inputChannel, scheduleHooks(ctx, start))
+				if checked.Cmp(start) >= 0 {
+					intervals.Append(new(collision.Interval).Set(start, checked))
+				}
+				next = new(big.Int).Add(checked, big.NewInt(1))
+			}
 		}
 	}
 
@@ -80,13 +118,14 @@ func runApplication(ctx *app_context.AppCtx) {
 // Returns:
 // - *app_context.AppCtx: The application context containing parameters, wallet ranges, intervals, and result paths.
 func createAppContext() *app_context.AppCtx {
-	ranges, wallets := utils.LoadData()
+	ranges, wallets, invalidWallets := utils.LoadData()
 	params := utils.GetParameters(*wallets)
+	utils.HandleInvalidWallets(invalidWallets, *params)
 
 	rootDir := utils.GetRootDir()
-	collisionPathFile := filepath.Join(rootDir, "data", fmt.Sprintf("wallet-%d-progress.json", params.TargetWallet))
+	intervals, collisionPathFile := openProgress(*ranges, *params)
+	journal := openJournal(intervals, collisionPathFile, *params)
 	resultPathFile := filepath.Join(rootDir, "results.json")
-	intervals := collision.ReadOrNew(collisionPathFile)
 	results := output_results.ReadOrNew(resultPathFile)
 
 	return &app_context.AppCtx{
@@ -95,6 +134,8 @@ func createAppContext() *app_context.AppCtx {
 		Wallets:           wallets,
 		Intervals:         intervals,
 		Results:           results,
+		Journal:           journal,
+		Stop:              stopOnSignal(),
 		CollisionPathFile: collisionPathFile,
 		ResultPathFile:    resultPathFile}
 }
@@ -107,7 +148,7 @@ func createAppContext() *app_context.AppCtx {
 // - outputChannel: The channel used to receive output data from workers.
 // - workerGroup: The WaitGroup used to synchronize worker goroutines.
 // - outputGroup: The WaitGroup used to synchronize output handler goroutines.
-func stopAndWaitWorkers(inputChannel, outputChannel chan *big.Int, workerGroup, outputGroup *sync.WaitGroup) {
+func stopAndWaitWorkers(inputChannel chan core.WorkUnit, outputChannel chan domain.Match, workerGroup, outputGroup *sync.WaitGroup) {
 	close(inputChannel)
 	workerGroup.Wait()
 	close(outputChannel)
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/collision"
+	"GoKeyHunt/internal/console"
+	"GoKeyHunt/internal/core"
+	"GoKeyHunt/internal/permutation"
+	"GoKeyHunt/internal/utils"
+	"fmt"
+	"path/filepath"
+)
+
+// schedulePermutation schedules the blocks of the wallet range in the order of a seeded permutation.
+// The range is split into blocks of BatchSize keys and every batch scans the block at the permutation cursor,
+// unless the progress intervals already cover it. A new permutation is keyed by the seed of the run. The seed and
+// the cursor are saved next to the progress file when the permutation starts and after every block, so the next
+// run continues with the following block. A block interrupted by a stop saves the keys it has checked but keeps
+// the cursor, so the next run scans that block again.
+//
+// Parameters:
+// - ctx: The application context containing configuration parameters, wallet ranges and intervals.
+// - inputChannel: The channel work units are sent to.
+func schedulePermutation(ctx *app_context.AppCtx, inputChannel chan core.WorkUnit) {
+	params, intervals := *ctx.Params, ctx.Intervals
+	start, end := utils.GetWalletStartAndEnd(*ctx.WalletRanges, params)
+	orderPath := filepath.Join(utils.GetRootDir(), "data", fmt.Sprintf("wallet-%d-permutation.json", params.TargetWallet))
+	order, resumed := permutation.ReadOrNew(orderPath, start, end, params.BatchSize, utils.SeedBytes(params.Seed))
+	console.PrintBlockOrderIfVerbose(order, resumed, params)
+	if !resumed {
+		order.Save(orderPath)
+	}
+
+	for i := 0; (i < params.BatchCount || params.BatchCount == -1) && !order.Done() && !stopped(ctx.Stop); i++ {
+		blockStart, blockEnd := order.Current()
+		console.PrintSummaryIfVerbose(start, blockStart, end, params, i+1)
+
+		interval := new(collision.Interval).Set(blockStart, blockEnd)
+		if !intervals.Covers(interval) {
+			checked := core.Schedule(blockStart, blockEnd, params, inputChannel, scheduleHooks(ctx, blockStart))
+			if checked.Cmp(blockStart) >= 0 {
+				intervals.Append(new(collision.Interval).Set(blockStart, checked))
+			}
+			if checked.Cmp(blockEnd) < 0 {
+				break
+			}
+		}
+		order.Advance()
+		order.Save(orderPath)
+	}
+}
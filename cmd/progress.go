@@ -0,0 +1,265 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/collision"
+	"GoKeyHunt/internal/core"
+	"GoKeyHunt/internal/domain"
+	"GoKeyHunt/internal/utils"
+	"GoKeyHunt/internal/utils/atomicfile"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+const progressUsage = "Usage: GoKeyHunt progress migrate [-w wallet] input output"
+
+// openProgress opens the progress store of the target wallet. Intervals are read from the binary progress file or,
+// if only the older JSON file exists, from the JSON file, which keeps being written as JSON; new progress is saved
+// in the binary format. When a bitmap block size is set, a memory-mapped bitmap is used instead, and the blocks the
+// intervals cover are marked in it every time it opens, so progress saved by runs without a bitmap is kept.
+//
+// Parameters:
+// - ranges: The wallet ranges.
+// - params: The parameters of the run.
+//
+// Returns:
+// - collision.Store: The progress store.
+// - string: The path of the file the progress is saved to.
+func openProgress(ranges domain.Ranges, params domain.Parameters) (collision.Store, string) {
+	dataDir := filepath.Join(utils.GetRootDir(), "data")
+	start, end := utils.GetWalletStartAndEnd(ranges, params)
+	intervalsPath := filepath.Join(dataDir, fmt.Sprintf("wallet-%d-progress.bin", params.TargetWallet))
+	if jsonPath := filepath.Join(dataDir, fmt.Sprintf("wallet-%d-progress.json", params.TargetWallet)); !exists(intervalsPath) && exists(jsonPath) {
+		intervalsPath = jsonPath
+	}
+	intervals := readProgress(intervalsPath, start, end)
+	if params.BitmapBlockSize == 0 {
+		return intervals, intervalsPath
+	}
+
+	bitmapPath := filepath.Join(dataDir, fmt.Sprintf("wallet-%d-progress.bitmap", params.TargetWallet))
+	bitmap, _, err := collision.OpenBitmap(bitmapPath, start, end, params.BitmapBlockSize)
+	if err != nil {
+		log.Fatalf("\nError: %v", err)
+	}
+	bitmap.AppendIntervals(intervals)
+	return bitmap, bitmapPath
+}
+
+// readProgress reads the progress intervals of a wallet range, falling back to the backup of the last save if the
+// file is missing or damaged. Missing files start empty progress, while files that cannot be read, fail their
+// checksum or belong to another range stop the program, so they are not overwritten.
+//
+// Parameters:
+// - filePath: The path of the progress file, in either format.
+// - start: The smallest key of the wallet range.
+// - end: The largest key of the wallet range.
+//
+// Returns:
+// - *collision.IntervalArray: The progress intervals, with the range of the wallet in their header.
+func readProgress(filePath string, start, end *big.Int) *collision.IntervalArray {
+	intervals, fromBackup, err := atomicfile.ReadWithBackup(filePath, collision.Read)
+	if fromBackup {
+		log.Printf("Warning: %s is missing or damaged, resuming from %s", filePath, filePath+atomicfile.BackupSuffix)
+	}
+	switch {
+	case os.IsNotExist(err):
+		intervals = collision.NewEmptyIntervalArray()
+	case err != nil:
+		log.Fatalf("\nError: %v", err)
+	case !intervals.Header().Matches(start, end):
+		log.Fatalf("\nError: %s was written for another wallet range.", filePath)
+	}
+	intervals.SetHeader(collision.ProgressHeader{Min: start, Max: end, BlockSize: max(intervals.Header().BlockSize, 1)})
+	return intervals
+}
+
+// openJournal opens the journal of completed work units that sits next to the progress file. Units recorded by a
+// run that crashed or was killed are replayed into the progress store, which is then saved so the journal can be
+// compacted.
+//
+// Parameters:
+// - intervals: The progress store.
+// - progressPath: The path of the progress file.
+// - params: The parameters of the run.
+//
+// Returns:
+// - *collision.Journal: The open journal.
+func openJournal(intervals collision.Store, progressPath string, params domain.Parameters) *collision.Journal {
+	journalPath := filepath.Join(filepath.Dir(progressPath), fmt.Sprintf("wallet-%d-progress.journal", params.TargetWallet))
+	journal, replayed, err := collision.OpenJournal(journalPath, intervals)
+	if err != nil {
+		log.Fatalf("\nError: %v", err)
+	}
+	if replayed > 0 {
+		log.Printf("Recovered %d completed work units from %s", replayed, journalPath)
+		if intervals.Save(progressPath) {
+			journal.Clear()
+		}
+	}
+	return journal
+}
+
+// recordUnit returns a function that records completed work units in a journal.
+//
+// Parameters:
+// - journal: The journal of the run.
+//
+// Returns:
+// - func(core.WorkUnit): The function to use as the Record hook of core.Schedule.
+func recordUnit(journal *collision.Journal) func(unit core.WorkUnit) {
+	return func(unit core.WorkUnit) {
+		end := new(big.Int).Add(unit.Start, big.NewInt(unit.Count-1))
+		if err := journal.Record(new(collision.Interval).Set(unit.Start, end)); err != nil {
+			log.Println("Error on write journal:", err)
+		}
+	}
+}
+
+// scheduleHooks returns the hooks of a range scheduled from start: completed units go to the journal, checkpoints
+// append the checked prefix of the range to the progress store and save it, and the schedule stops with the run.
+// Each checkpoint appends a longer prefix of the same range, which the store merges with the previous one, so the
+// progress keeps a single interval for the range however many checkpoints are taken.
+//
+// Parameters:
+// - ctx: The application context containing the progress store, its path, the journal and the stop channel.
+// - start: The first key of the scheduled range.
+//
+// Returns:
+// - core.Hooks: The hooks to pass to core.Schedule.
+func scheduleHooks(ctx *app_context.AppCtx, start *big.Int) core.Hooks {
+	return core.Hooks{
+		Record: recordUnit(ctx.Journal),
+		Checkpoint: func(checked *big.Int) {
+			ctx.Intervals.Append(new(collision.Interval).Set(start, checked))
+			saveProgress(ctx)
+		},
+		Stop: ctx.Stop,
+	}
+}
+
+// saveProgress saves the progress store and, once it is safely on disk, compacts the journal.
+//
+// Parameters:
+// - ctx: The application context containing the progress store, its path and the journal.
+func saveProgress(ctx *app_context.AppCtx) {
+	if ctx.Intervals.Save(ctx.CollisionPathFile) {
+		if err := ctx.Journal.Clear(); err != nil {
+			log.Println("Error on compact journal:", err)
+		}
+	}
+}
+
+// closeProgress saves the progress store and closes the journal at the end of a run.
+//
+// Parameters:
+// - ctx: The application context containing the progress store, its path and the journal.
+func closeProgress(ctx *app_context.AppCtx) {
+	saveProgress(ctx)
+	ctx.Journal.Close()
+}
+
+// closeStore releases the file a progress store holds open, as the bitmap does. The store cannot be used
+// afterwards, so this runs once the end summary has read it.
+//
+// Parameters:
+// - intervals: The progress store.
+func closeStore(intervals collision.Store) {
+	if closer, ok := intervals.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Println("Error on close progress file:", err)
+		}
+	}
+}
+
+// runProgressCommand runs the progress subcommand. "progress migrate" converts a progress file between the JSON and
+// the binary format, or exports a bitmap to either of them: the input format is detected from its content and the
+// output format is chosen by the output extension, JSON for ".json" and binary otherwise. The wallet range written in a binary header is taken from
+// ranges.json when -w is set, and from the input otherwise.
+//
+// Parameters:
+// - args: The command-line arguments following "progress".
+func runProgressCommand(args []string) {
+	if len(args) == 0 || args[0] != "migrate" {
+		fmt.Println(progressUsage)
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet("progress migrate", flag.ExitOnError)
+	targetWallet := flags.Int("w", 0, "Wallet whose range is written in the binary header. If 0, the range of the input is kept.")
+	flags.Usage = func() {
+		fmt.Println(progressUsage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(args[1:])
+	if flags.NArg() != 2 {
+		flags.Usage()
+		log.Fatalf("\nError: Expected an input and an output file.")
+	}
+	input, output := flags.Arg(0), flags.Arg(1)
+
+	if strings.EqualFold(filepath.Ext(output), ".bitmap") {
+		log.Fatalf("\nError: Bitmaps cannot be written by migrate; run with -bm to convert the progress to a bitmap.")
+	}
+
+	read := collision.Read
+	if inputFormat(input) == "bitmap" {
+		read = collision.ReadBitmap
+	}
+	intervals, err := read(input)
+	if err != nil {
+		log.Fatalf("\nError: %v", err)
+	}
+	if *targetWallet != 0 {
+		ranges, _, _ := utils.LoadData()
+		if *targetWallet < 0 || *targetWallet >= len(ranges.Ranges) {
+			log.Fatalf("\nError: Wallet must be between 1 and %d.", len(ranges.Ranges)-1)
+		}
+		start, end := utils.GetWalletStartAndEnd(*ranges, domain.Parameters{TargetWallet: *targetWallet})
+		header := intervals.Header()
+		if !header.Matches(start, end) {
+			log.Fatalf("\nError: %s was written for another wallet range.", input)
+		}
+		intervals.SetHeader(collision.ProgressHeader{Min: start, Max: end, BlockSize: header.BlockSize})
+	}
+	if _, err := os.Stat(output); err == nil {
+		log.Fatalf("\nError: %s already exists.", output)
+	}
+	if !intervals.Save(output) {
+		os.Exit(1)
+	}
+
+	inputInfo, _ := os.Stat(input)
+	outputInfo, _ := os.Stat(output)
+	fmt.Printf("- Intervals: %s\n", humanize.Comma(int64(intervals.Size())))
+	fmt.Printf("- Keys covered: %s\n", humanize.BigComma(intervals.CalculateTotalProgress()))
+	fmt.Printf("- %s (%s): %s\n", input, inputFormat(input), humanize.Bytes(uint64(inputInfo.Size())))
+	fmt.Printf("- %s (%s): %s\n", output, collision.FormatOf(output), humanize.Bytes(uint64(outputInfo.Size())))
+}
+
+// inputFormat returns the name of the format of a progress file, detected from the magic its content starts with.
+func inputFormat(filePath string) string {
+	data := make([]byte, 4)
+	if file, err := os.Open(filePath); err == nil {
+		n, _ := io.ReadFull(file, data)
+		data = data[:n]
+		file.Close()
+	}
+	if collision.IsBitmap(data) {
+		return "bitmap"
+	}
+	return collision.DetectFormat(data).String()
+}
+
+// exists reports whether a file exists.
+func exists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
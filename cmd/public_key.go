@@ -0,0 +1,72 @@
+package main
+
+import (
+	"GoKeyHunt/internal/app_context"
+	"GoKeyHunt/internal/domain"
+	"GoKeyHunt/internal/output_results"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// targetPublicKey returns the public key searched by the kangaroo and BSGS modes: the -pk flag or, if the flag is
+// empty, the target wallet itself, which must then be a public key.
+//
+// Parameters:
+// - params: The application configuration parameters.
+// - wallets: The wallet addresses.
+//
+// Returns:
+// - []byte: The public key as given, compressed or uncompressed.
+// - *secp256k1.PublicKey: The parsed public key.
+func targetPublicKey(params domain.Parameters, wallets domain.Wallets) ([]byte, *secp256k1.PublicKey) {
+	if params.TargetWallet == 0 {
+		log.Fatalf("\nError: The %v mode needs a single target wallet, -w 0 is not supported.", params.SearchMode)
+	}
+
+	rawPublicKey := params.PublicKey
+	if rawPublicKey == nil {
+		entry := wallets.Entry(params.TargetWallet - 1)
+		if entry.Type != domain.PubKey {
+			log.Fatalf("\nError: Wallet %d is a %v address. Use -pk to give its public key.", params.TargetWallet, entry.Type)
+		}
+		rawPublicKey, _ = hex.DecodeString(entry.Address)
+	}
+	publicKey, err := secp256k1.ParsePubKey(rawPublicKey)
+	if err != nil {
+		log.Fatalf("\nError: Invalid target public key: %v", err)
+	}
+	return rawPublicKey, publicKey
+}
+
+// saveKeyResult records the key found for the target wallet by the kangaroo or BSGS mode in the results file.
+//
+// Parameters:
+// - ctx: The application context containing configuration parameters, wallets, and results.
+// - key: The private key found.
+// - rawPublicKey: The target public key as given, which decides the WIF compression.
+func saveKeyResult(ctx *app_context.AppCtx, key *big.Int, rawPublicKey []byte) {
+	params := *ctx.Params
+	match := domain.Match{
+		Key:    key,
+		Wallet: params.TargetWallet - 1,
+		Info: domain.MatchInfo{
+			Matcher:      params.SearchMode.String(),
+			PubKeyFormat: "compressed",
+			Compressed:   len(rawPublicKey) == secp256k1.PubKeyBytesLenCompressed,
+		},
+	}
+	if !match.Info.Compressed {
+		match.Info.PubKeyFormat = "uncompressed"
+	}
+	result := output_results.NewResult(match, *ctx.Wallets)
+	if ctx.Results.AppendIfNotExist(*result) {
+		ctx.Results.Save(ctx.ResultPathFile)
+	}
+	if params.VerboseKeyFind {
+		fmt.Printf("\nFound key for the wallet: %d\n%s\n", result.WalletIndex, result)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// stopOnSignal returns a channel that is closed on the first SIGINT or SIGTERM, so the running search stops
+// sending work, drains the workers and saves what it has checked. A second signal exits at once; the work units
+// completed so far are still in the journal and are recovered by the next run.
+//
+// Returns:
+// - <-chan struct{}: A channel closed when the search should stop.
+func stopOnSignal() <-chan struct{} {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stop := make(chan struct{})
+	go func() {
+		received := <-signals
+		log.Printf("Received %v: finishing the work units in flight and saving progress. Repeat to exit at once.", received)
+		close(stop)
+		received = <-signals
+		log.Printf("Received %v again: exiting without saving progress.", received)
+		os.Exit(1)
+	}()
+	return stop
+}
+
+// stopped reports whether the search has been asked to stop.
+//
+// Parameters:
+// - stop: The channel returned by stopOnSignal.
+//
+// Returns:
+// - bool: True if stop has been closed.
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -15,8 +15,10 @@ import (
 // - Params: A pointer to domain.Parameters, which contains configuration parameters for the application.
 // - WalletRanges: A pointer to domain.Ranges, which defines the ranges of wallet addresses to be processed.
 // - Wallets: A pointer to domain.Wallets, which contains the wallet addresses to be searched.
-// - Intervals: A pointer to collision.IntervalArray, which manages intervals of collision results.
+// - Intervals: A collision.Store, which records the searched keys as intervals or as a bitmap of blocks.
 // - Results: A pointer to output_results.ResultArray, which stores the results of key searches.
+// - Journal: A pointer to collision.Journal, which records every completed work unit until the progress is saved.
+// - Stop: A channel closed when the search has been interrupted and should stop after saving its progress.
 //
 // - CollisionPathFile: A string representing the file path where collision data is saved.
 // - ResultPathFile: A string representing the file path where result data is saved.
@@ -24,8 +26,10 @@ type AppCtx struct {
 	Params       *domain.Parameters          // Application configuration parameters.
 	WalletRanges *domain.Ranges              // Ranges of wallet addresses to be processed.
 	Wallets      *domain.Wallets             // Wallet addresses to search against.
-	Intervals    *collision.IntervalArray    // Array of collision intervals.
+	Intervals    collision.Store             // Store of the searched keys.
 	Results      *output_results.ResultArray // Array of search results.
+	Journal      *collision.Journal          // Journal of completed work units.
+	Stop         <-chan struct{}             // Closed when the search should stop.
 
 	CollisionPathFile string // File path for saving collision data.
 	ResultPathFile    string // File path for saving result data.
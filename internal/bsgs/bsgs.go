@@ -0,0 +1,167 @@
+// Package bsgs implements the baby-step giant-step method for private keys whose public key is known and that
+// lie in a bounded range.
+//
+// The baby steps j*G for j in [1, m] are stored in a Table. Giant step i looks at the point target - c_i*G, where
+// the centers c_i are 2m+1 keys apart: if that point is +-j*G for a baby step j, the key is c_i +- j. Each giant
+// step therefore covers the 2m+1 keys [c_i - m, c_i + m] with one point addition and one table lookup.
+package bsgs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Solver runs giant steps over a range against a baby-step Table.
+type Solver struct {
+	table     *Table
+	min, max  *big.Int
+	stride    *big.Int
+	steps     uint64
+	target    secp256k1.JacobianPoint
+	negStride secp256k1.JacobianPoint
+
+	found atomic.Pointer[big.Int]
+}
+
+// NewSolver creates a Solver that searches the key of target in [min, max] with the given baby-step table.
+//
+// Parameters:
+// - table: The baby steps; its size m sets the 2m+1 keys covered by every giant step.
+// - target: The public key whose private key is searched.
+// - min: The smallest private key of the range.
+// - max: The largest private key of the range.
+//
+// Returns:
+// - *Solver: A pointer to the newly created Solver.
+// - error: An error if the range is invalid or needs more than 2^64-1 giant steps.
+func NewSolver(table *Table, target *secp256k1.PublicKey, min, max *big.Int) (*Solver, error) {
+	if min.Sign() <= 0 || min.Cmp(max) > 0 || max.Cmp(secp256k1.S256().N) >= 0 {
+		return nil, fmt.Errorf("invalid range [%x, %x]", min, max)
+	}
+	if table.Size() == 0 {
+		return nil, errors.New("empty baby-step table")
+	}
+
+	s := &Solver{table: table, min: min, max: max}
+	s.stride = new(big.Int).SetUint64(table.Size())
+	s.stride.Lsh(s.stride, 1).Add(s.stride, big.NewInt(1))
+
+	width := new(big.Int).Sub(max, min)
+	steps := width.Div(width, s.stride).Add(width, big.NewInt(1))
+	if !steps.IsUint64() {
+		return nil, fmt.Errorf("range needs %v giant steps; use a larger baby-step table", steps)
+	}
+	s.steps = steps.Uint64()
+
+	target.AsJacobian(&s.target)
+	var stride secp256k1.ModNScalar
+	stride.SetByteSlice(s.stride.Bytes())
+	secp256k1.ScalarBaseMultNonConst(stride.Negate(), &s.negStride)
+	return s, nil
+}
+
+// GiantSteps returns the number of giant steps that cover the whole range.
+//
+// Returns:
+// - uint64: The number of giant steps.
+func (s *Solver) GiantSteps() uint64 {
+	return s.steps
+}
+
+// Covered returns the keys covered by the giant steps first to last, clipped to the range.
+//
+// Parameters:
+// - first: The index of the first giant step.
+// - last: The index of the last giant step.
+//
+// Returns:
+// - *big.Int: The smallest covered key.
+// - *big.Int: The largest covered key.
+func (s *Solver) Covered(first, last uint64) (*big.Int, *big.Int) {
+	start := new(big.Int).SetUint64(first)
+	start.Mul(start, s.stride).Add(start, s.min)
+	end := new(big.Int).SetUint64(last + 1)
+	end.Mul(end, s.stride).Add(end, s.min).Sub(end, big.NewInt(1))
+	if end.Cmp(s.max) > 0 {
+		end.Set(s.max)
+	}
+	return start, end
+}
+
+// Found returns the key found by any Search call, or nil.
+//
+// Returns:
+// - *big.Int: The private key of the target, or nil.
+func (s *Solver) Found() *big.Int {
+	return s.found.Load()
+}
+
+// Search runs the giant steps first to last, batchSize at a time, and stops early once any Search call found
+// the key. It is safe to call from several goroutines.
+//
+// Parameters:
+// - first: The index of the first giant step.
+// - last: The index of the last giant step (less than GiantSteps).
+// - batchSize: The number of giant steps that share one modular inversion.
+//
+// Returns:
+// - *big.Int: The private key of the target, or nil if it is not in the keys covered by these giant steps.
+func (s *Solver) Search(first, last uint64, batchSize int) *big.Int {
+	// The point of giant step i is target - c_i*G, with c_i = min + m + i*(2m+1).
+	center := new(big.Int).SetUint64(first)
+	center.Mul(center, s.stride).Add(center, s.min).Add(center, new(big.Int).SetUint64(s.table.Size()))
+	var scalar secp256k1.ModNScalar
+	var point secp256k1.JacobianPoint
+	scalar.SetByteSlice(center.Bytes())
+	secp256k1.ScalarBaseMultNonConst(scalar.Negate(), &point)
+	secp256k1.AddNonConst(&point, &s.target, &point)
+
+	stepper := newStepper(batchSize)
+	for step := first; step <= last && s.found.Load() == nil; {
+		count := int(min(last-step+1, uint64(batchSize)))
+		keys := stepper.walk(&point, &s.negStride, count)
+		for k, key := range keys {
+			if stepper.infinity[k] {
+				s.check(center, 0)
+			} else {
+				s.table.find(key, func(multiple uint64) bool { return s.check(center, multiple) })
+			}
+			center.Add(center, s.stride)
+		}
+		step += uint64(count)
+	}
+	return s.found.Load()
+}
+
+// check tries the keys center - multiple and center + multiple and records the one that produces the target.
+//
+// Returns:
+// - bool: True if the key was found.
+func (s *Solver) check(center *big.Int, multiple uint64) bool {
+	offset := new(big.Int).SetUint64(multiple)
+	for _, key := range []*big.Int{new(big.Int).Sub(center, offset), new(big.Int).Add(center, offset)} {
+		if s.verify(key) {
+			s.found.CompareAndSwap(nil, key)
+			return true
+		}
+	}
+	return false
+}
+
+// verify reports whether a private key produces the target public key.
+func (s *Solver) verify(key *big.Int) bool {
+	var scalar secp256k1.ModNScalar
+	if key.Sign() <= 0 || scalar.SetByteSlice(key.Bytes()) {
+		return false
+	}
+	var point, target secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&scalar, &point)
+	point.ToAffine()
+	target.Set(&s.target)
+	target.ToAffine()
+	return point.X.Equals(&target.X) && point.Y.Equals(&target.Y)
+}
@@ -0,0 +1,100 @@
+package bsgs
+
+import (
+	"GoKeyHunt/internal/utils"
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+// searchKey searches key in [min, max] with a table of baby steps and fails the test if it is not found.
+func searchKey(t *testing.T, table *Table, key, min, max *big.Int) {
+	t.Helper()
+	solver, err := NewSolver(table, utils.CreatePublicPoint(key), min, max)
+	if err != nil {
+		t.Fatalf("NewSolver: %v", err)
+	}
+	result := solver.Search(0, solver.GiantSteps()-1, 64)
+	if result == nil || result.Cmp(key) != 0 {
+		t.Errorf("expected %x, got %x", key, result)
+	}
+}
+
+func TestSolver_SolvedPuzzles(t *testing.T) {
+	table := BuildTable(1<<12, 4)
+	puzzles := map[uint]int64{20: 0xd2c55, 25: 0x1fa5ee5, 30: 0x3d94cd64}
+	for bits, key := range puzzles {
+		min := new(big.Int).Lsh(big.NewInt(1), bits-1)
+		max := new(big.Int).Sub(new(big.Int).Lsh(min, 1), big.NewInt(1))
+		searchKey(t, table, big.NewInt(key), min, max)
+	}
+}
+
+func TestSolver_EveryKeyOfASmallRange(t *testing.T) {
+	table := BuildTable(5, 1)
+	min, max := big.NewInt(3), big.NewInt(40)
+	for key := new(big.Int).Set(min); key.Cmp(max) <= 0; key.Add(key, big.NewInt(1)) {
+		searchKey(t, table, key, min, max)
+	}
+}
+
+func TestSolver_KeyOutsideRange(t *testing.T) {
+	table := BuildTable(16, 1)
+	solver, err := NewSolver(table, utils.CreatePublicPoint(big.NewInt(5000)), big.NewInt(1000), big.NewInt(2000))
+	if err != nil {
+		t.Fatalf("NewSolver: %v", err)
+	}
+	if result := solver.Search(0, solver.GiantSteps()-1, 8); result != nil {
+		t.Errorf("expected %v, got %v", nil, result)
+	}
+}
+
+func TestSolver_Covered(t *testing.T) {
+	table := BuildTable(4, 1)
+	solver, err := NewSolver(table, utils.CreatePublicPoint(big.NewInt(7)), big.NewInt(10), big.NewInt(40))
+	if err != nil {
+		t.Fatalf("NewSolver: %v", err)
+	}
+	// Every giant step covers 2*4+1 = 9 keys, so [10, 40] needs 4 steps, the last one clipped.
+	if solver.GiantSteps() != 4 {
+		t.Errorf("expected %v, got %v", 4, solver.GiantSteps())
+	}
+	start, end := solver.Covered(1, 2)
+	if start.Int64() != 19 || end.Int64() != 36 {
+		t.Errorf("expected [19, 36], got [%v, %v]", start, end)
+	}
+	if _, end := solver.Covered(3, 3); end.Int64() != 40 {
+		t.Errorf("expected %v, got %v", 40, end)
+	}
+}
+
+func TestTable_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baby.tbl")
+	// More baby steps than one I/O chunk, so the file is written and read in several chunks.
+	size := uint64(ioChunk + 1000)
+	built, created, err := LoadOrBuildTable(path, size, 2)
+	if err != nil || !created {
+		t.Fatalf("expected a new table, got created %v and error %v", created, err)
+	}
+	loaded, created, err := LoadOrBuildTable(path, size, 2)
+	if err != nil || created {
+		t.Fatalf("expected a loaded table, got created %v and error %v", created, err)
+	}
+	for i := range built.keys {
+		if built.keys[i] != loaded.keys[i] || built.multiples[i] != loaded.multiples[i] {
+			t.Fatalf("expected entry %d to be %x/%d, got %x/%d", i, built.keys[i], built.multiples[i], loaded.keys[i], loaded.multiples[i])
+		}
+	}
+	if _, err := LoadTable(path, size-1); err == nil {
+		t.Errorf("expected an error for a table of another size")
+	}
+}
+
+func TestTableSize(t *testing.T) {
+	cases := map[int64]uint64{0: 0, EntrySize - 1: 0, 120: 10, 1 << 40: MaxTableSize}
+	for memory, expected := range cases {
+		if result := TableSize(memory); result != expected {
+			t.Errorf("expected %v, got %v", expected, result)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package bsgs
+
+import (
+	"GoKeyHunt/internal/core"
+	"encoding/binary"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// generator is the base point G in Jacobian coordinates.
+var generator = func() secp256k1.JacobianPoint {
+	var one secp256k1.ModNScalar
+	var point secp256k1.JacobianPoint
+	one.SetInt(1)
+	secp256k1.ScalarBaseMultNonConst(&one, &point)
+	return point
+}()
+
+// stepper walks an arithmetic progression of points and returns the fingerprint of every x coordinate.
+// The points are added in Jacobian coordinates and brought to affine coordinates with one batched inversion.
+type stepper struct {
+	points   []secp256k1.JacobianPoint
+	zs       []secp256k1.FieldVal
+	scratch  []secp256k1.FieldVal
+	keys     []uint64
+	infinity []bool
+}
+
+// newStepper creates a stepper that walks at most size points at once.
+func newStepper(size int) *stepper {
+	return &stepper{
+		points:   make([]secp256k1.JacobianPoint, size),
+		zs:       make([]secp256k1.FieldVal, size),
+		scratch:  make([]secp256k1.FieldVal, size),
+		keys:     make([]uint64, size),
+		infinity: make([]bool, size),
+	}
+}
+
+// walk computes the fingerprints of the points start + k*step for k in [0, count) and advances start by
+// count*step. The point at infinity has no x coordinate: its fingerprint is 0 and s.infinity[k] is set.
+// The returned slice is reused by the next call.
+func (s *stepper) walk(start, step *secp256k1.JacobianPoint, count int) []uint64 {
+	for k := 0; k < count; k++ {
+		s.points[k].Set(start)
+		s.infinity[k] = start.Z.IsZero()
+		if s.infinity[k] {
+			s.zs[k].SetInt(1)
+		} else {
+			s.zs[k].Set(&start.Z)
+		}
+		secp256k1.AddNonConst(start, step, start)
+	}
+	core.BatchInverse(s.zs[:count], s.scratch)
+
+	var zInv2, x secp256k1.FieldVal
+	var xBytes [32]byte
+	for k := 0; k < count; k++ {
+		if s.infinity[k] {
+			s.keys[k] = 0
+			continue
+		}
+		zInv2.SquareVal(&s.zs[k])
+		x.Mul2(&s.points[k].X, &zInv2).Normalize()
+		x.PutBytesUnchecked(xBytes[:])
+		s.keys[k] = binary.BigEndian.Uint64(xBytes[24:])
+	}
+	return s.keys[:count]
+}
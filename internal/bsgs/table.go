@@ -0,0 +1,281 @@
+package bsgs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// EntrySize is the number of bytes one baby step takes in memory and on disk: a 64-bit x coordinate fingerprint
+// and a 32-bit multiple of G.
+const EntrySize = 12
+
+// MaxTableSize is the largest number of baby steps a Table can hold, bounded by its 32-bit multiples.
+const MaxTableSize = math.MaxUint32
+
+// tableMagic identifies a baby-step table file; its last byte is the format version.
+var tableMagic = [8]byte{'G', 'K', 'H', 'B', 'S', 'G', 'S', 1}
+
+// ioChunk is the number of fingerprints or multiples encoded at a time when a Table is saved or loaded, so the
+// file is streamed through a small buffer instead of a second copy of the whole table.
+const ioChunk = 1 << 16
+
+// buildChunk is the number of consecutive baby steps a worker computes with one batched inversion.
+const buildChunk = 1024
+
+// Table holds the baby steps j*G for j in [1, size], sorted by the fingerprint of their x coordinate.
+// A fingerprint is the low 64 bits of x, so a lookup may return false positives that the caller must verify;
+// -j*G shares the x coordinate of j*G, so every entry stands for both points.
+type Table struct {
+	size      uint64
+	keys      []uint64
+	multiples []uint32
+}
+
+// TableSize returns the number of baby steps that fit in a memory budget, at most MaxTableSize.
+//
+// Parameters:
+// - memory: The memory budget in bytes.
+//
+// Returns:
+// - uint64: The number of baby steps, or 0 if the budget is too small for one.
+func TableSize(memory int64) uint64 {
+	if memory < EntrySize {
+		return 0
+	}
+	return min(uint64(memory)/EntrySize, MaxTableSize)
+}
+
+// BuildTable computes and sorts the baby steps j*G for j in [1, size], spreading the work across workers.
+//
+// Parameters:
+// - size: The number of baby steps (from 1 to MaxTableSize).
+// - workers: The number of goroutines used to compute the points.
+//
+// Returns:
+// - *Table: A pointer to the newly built Table.
+func BuildTable(size uint64, workers int) *Table {
+	t := &Table{size: size, keys: make([]uint64, size), multiples: make([]uint32, size)}
+
+	chunks := make(chan uint64, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			stepper := newStepper(buildChunk)
+			for first := range chunks {
+				count := min(size-first+1, buildChunk)
+				var scalar secp256k1.ModNScalar
+				var start secp256k1.JacobianPoint
+				scalar.SetInt(uint32(first))
+				secp256k1.ScalarBaseMultNonConst(&scalar, &start)
+
+				keys := stepper.walk(&start, &generator, int(count))
+				for k, key := range keys {
+					t.keys[first-1+uint64(k)] = key
+					t.multiples[first-1+uint64(k)] = uint32(first + uint64(k))
+				}
+			}
+		}()
+	}
+	for first := uint64(1); first <= size; first += buildChunk {
+		chunks <- first
+	}
+	close(chunks)
+	wg.Wait()
+
+	sort.Sort(byKey{t})
+	return t
+}
+
+// Size returns the number of baby steps in the Table.
+//
+// Returns:
+// - uint64: The number of baby steps.
+func (t *Table) Size() uint64 {
+	return t.size
+}
+
+// find calls match with every multiple j whose x coordinate fingerprint equals key, until match returns true.
+func (t *Table) find(key uint64, match func(multiple uint64) bool) {
+	i := sort.Search(len(t.keys), func(i int) bool { return t.keys[i] >= key })
+	for ; i < len(t.keys) && t.keys[i] == key; i++ {
+		if match(uint64(t.multiples[i])) {
+			return
+		}
+	}
+}
+
+// byKey sorts the entries of a Table by fingerprint, keeping every multiple next to its fingerprint.
+type byKey struct{ *Table }
+
+// Len returns the number of entries.
+func (b byKey) Len() int { return len(b.keys) }
+
+// Swap exchanges the entries at positions i and j.
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.multiples[i], b.multiples[j] = b.multiples[j], b.multiples[i]
+}
+
+// Less returns true if the fingerprint at i is smaller than the fingerprint at j.
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+
+// Save writes the Table to a file: a magic and version header, the number of baby steps, then the sorted
+// fingerprints and their multiples, all little-endian.
+//
+// Parameters:
+// - path: The path of the file to write.
+//
+// Returns:
+// - error: An error if the file cannot be written.
+func (t *Table) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriterSize(file, 1<<20)
+	for _, data := range []any{tableMagic, t.size} {
+		if err := binary.Write(writer, binary.LittleEndian, data); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writeValues(writer, t.keys, 8, binary.LittleEndian.PutUint64); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writeValues(writer, t.multiples, 4, binary.LittleEndian.PutUint32); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadTable reads a Table written by Save and checks that it holds the expected number of baby steps.
+//
+// Parameters:
+// - path: The path of the file to read.
+// - size: The expected number of baby steps.
+//
+// Returns:
+// - *Table: A pointer to the loaded Table.
+// - error: An error if the file cannot be read, is not a baby-step table or has another size.
+func LoadTable(path string, size uint64) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := bufio.NewReaderSize(file, 1<<20)
+
+	var magic [8]byte
+	var storedSize uint64
+	if err := binary.Read(reader, binary.LittleEndian, &magic); err != nil {
+		return nil, err
+	}
+	if magic != tableMagic {
+		return nil, errors.New("not a baby-step table file")
+	}
+	if err := binary.Read(reader, binary.LittleEndian, &storedSize); err != nil {
+		return nil, err
+	}
+	if storedSize != size {
+		return nil, fmt.Errorf("table holds %d baby steps, expected %d", storedSize, size)
+	}
+
+	t := &Table{size: size, keys: make([]uint64, size), multiples: make([]uint32, size)}
+	if err := readValues(reader, t.keys, 8, binary.LittleEndian.Uint64); err != nil {
+		return nil, fmt.Errorf("truncated baby-step table: %w", err)
+	}
+	if err := readValues(reader, t.multiples, 4, binary.LittleEndian.Uint32); err != nil {
+		return nil, fmt.Errorf("truncated baby-step table: %w", err)
+	}
+	if _, err := reader.ReadByte(); err != io.EOF {
+		return nil, errors.New("trailing data after baby-step table")
+	}
+	return t, nil
+}
+
+// writeValues writes fixed-size values through a buffer of at most ioChunk values.
+//
+// Parameters:
+// - writer: The destination of the encoded values.
+// - values: The values to write.
+// - size: The number of bytes of one encoded value.
+// - put: The function that encodes one value into the start of a byte slice.
+//
+// Returns:
+// - error: An error if the values cannot be written.
+func writeValues[T uint32 | uint64](writer io.Writer, values []T, size int, put func([]byte, T)) error {
+	buffer := make([]byte, min(len(values), ioChunk)*size)
+	for len(values) > 0 {
+		n := min(len(values), ioChunk)
+		for i, value := range values[:n] {
+			put(buffer[i*size:], value)
+		}
+		if _, err := writer.Write(buffer[:n*size]); err != nil {
+			return err
+		}
+		values = values[n:]
+	}
+	return nil
+}
+
+// readValues fills values with fixed-size values read through a buffer of at most ioChunk values.
+//
+// Parameters:
+// - reader: The source of the encoded values.
+// - values: The slice to fill.
+// - size: The number of bytes of one encoded value.
+// - get: The function that decodes one value from the start of a byte slice.
+//
+// Returns:
+// - error: An error if the reader ends before values is filled.
+func readValues[T uint32 | uint64](reader io.Reader, values []T, size int, get func([]byte) T) error {
+	buffer := make([]byte, min(len(values), ioChunk)*size)
+	for len(values) > 0 {
+		n := min(len(values), ioChunk)
+		if _, err := io.ReadFull(reader, buffer[:n*size]); err != nil {
+			return err
+		}
+		for i := range values[:n] {
+			values[i] = get(buffer[i*size:])
+		}
+		values = values[n:]
+	}
+	return nil
+}
+
+// LoadOrBuildTable loads the Table saved at path, or builds it and saves it there when the file is missing or
+// does not hold size baby steps.
+//
+// Parameters:
+// - path: The path of the table file.
+// - size: The number of baby steps.
+// - workers: The number of goroutines used to build the table.
+//
+// Returns:
+// - *Table: A pointer to the loaded or built Table.
+// - bool: True if the table was built, false if it was loaded.
+// - error: An error if a built table could not be saved; the table is still returned.
+func LoadOrBuildTable(path string, size uint64, workers int) (*Table, bool, error) {
+	if t, err := LoadTable(path, size); err == nil {
+		return t, false, nil
+	}
+	t := BuildTable(size, workers)
+	return t, true, t.Save(path)
+}
@@ -0,0 +1,522 @@
+package collision
+
+import (
+	"GoKeyHunt/internal/utils/atomicfile"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"math/bits"
+	"os"
+)
+
+// BitmapVersion is the version of the bitmap file format written by this package.
+const BitmapVersion = 1
+
+// MaxBitmapSize is the largest bitmap, in bytes, a wallet range may need. A larger block size keeps wide ranges
+// under it.
+const MaxBitmapSize = 1 << 30
+
+// bitmapMagic starts every bitmap file.
+var bitmapMagic = [4]byte{'G', 'K', 'B', 'M'}
+
+// bitmapHeaderSize is the size of the file header: magic, version, block size, min and max.
+const bitmapHeaderSize = 4 + 1 + 8 + 32 + 32
+
+// Bitmap records the progress of a wallet range as one bit per block of BlockSize consecutive keys, in a file that
+// is memory-mapped so setting a bit costs no write call. A block counts as searched only once every key of it has
+// been searched, so the scheduler should hand out whole blocks: HandleIntervalCollision widens the interval it
+// returns to the blocks it touches. The last block of the range may be shorter than the others.
+type Bitmap struct {
+	path      string
+	file      *os.File
+	mapping   *mapping
+	bits      []byte
+	min, max  *big.Int
+	blockSize *big.Int
+	blocks    uint64
+	count     uint64
+}
+
+// OpenBitmap opens the bitmap of a wallet range, creating the file if it does not exist yet.
+//
+// Parameters:
+// - filePath: The path of the bitmap file.
+// - min: The smallest key of the range.
+// - max: The largest key of the range.
+// - blockSize: The number of keys each bit stands for (must be greater than 0).
+//
+// Returns:
+// - *Bitmap: A pointer to the mapped bitmap.
+// - bool: True if the file was created.
+// - error: An error if the file cannot be mapped or was written for another range or block size.
+func OpenBitmap(filePath string, min, max *big.Int, blockSize int64) (*Bitmap, bool, error) {
+	if blockSize < 1 || min.Cmp(max) > 0 {
+		return nil, false, errors.New("invalid bitmap range or block size")
+	}
+	size := big.NewInt(blockSize)
+	blocks := new(big.Int).Sub(max, min)
+	blocks.Div(blocks, size).Add(blocks, big.NewInt(1))
+	if !blocks.IsUint64() || blocks.Uint64() > MaxBitmapSize*8 {
+		return nil, false, fmt.Errorf("a block size of %d needs a bitmap larger than %d bytes", blockSize, MaxBitmapSize)
+	}
+	b := &Bitmap{
+		path:      filePath,
+		min:       new(big.Int).Set(min),
+		max:       new(big.Int).Set(max),
+		blockSize: size,
+		blocks:    blocks.Uint64(),
+	}
+	header := b.header()
+	fileSize := int64(len(header)) + int64((b.blocks+7)/8)
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, false, err
+	}
+	created := info.Size() == 0
+	if created {
+		_, err = file.Write(header)
+		if err == nil {
+			err = file.Truncate(fileSize)
+		}
+	} else {
+		err = checkBitmapHeader(file, header, info.Size(), fileSize)
+	}
+	if err != nil {
+		file.Close()
+		return nil, false, fmt.Errorf("%s: %v", filePath, err)
+	}
+
+	b.file = file
+	if b.mapping, err = mapFile(file, int(fileSize)); err != nil {
+		file.Close()
+		return nil, false, fmt.Errorf("%s: %v", filePath, err)
+	}
+	b.bits = b.mapping.data[bitmapHeaderSize:]
+	for _, value := range b.bits {
+		b.count += uint64(bits.OnesCount8(value))
+	}
+	return b, created, nil
+}
+
+// header encodes the header the bitmap is written with.
+func (b *Bitmap) header() []byte {
+	header := make([]byte, 0, bitmapHeaderSize)
+	header = append(header, bitmapMagic[:]...)
+	header = append(header, BitmapVersion)
+	header = binary.BigEndian.AppendUint64(header, b.blockSize.Uint64())
+	header = append(header, b.min.FillBytes(make([]byte, 32))...)
+	return append(header, b.max.FillBytes(make([]byte, 32))...)
+}
+
+// checkBitmapHeader checks that an existing file holds a bitmap of the expected header and size.
+func checkBitmapHeader(file *os.File, expected []byte, size, expectedSize int64) error {
+	header := make([]byte, bitmapHeaderSize)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return errors.New("truncated header")
+	}
+	switch {
+	case !bytes.Equal(header[:4], bitmapMagic[:]):
+		return errors.New("not a bitmap file")
+	case header[4] != BitmapVersion:
+		return fmt.Errorf("unsupported bitmap version %d", header[4])
+	case !bytes.Equal(header, expected):
+		return errors.New("written for another range or block size")
+	case size != expectedSize:
+		return fmt.Errorf("expected %d bytes, got %d", expectedSize, size)
+	}
+	return nil
+}
+
+// Close flushes the bitmap and releases the mapping and the file.
+//
+// Returns:
+// - error: An error if the bitmap cannot be flushed or unmapped.
+func (b *Bitmap) Close() error {
+	err := b.mapping.flush()
+	if unmapErr := b.mapping.unmap(); err == nil {
+		err = unmapErr
+	}
+	if closeErr := b.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Blocks returns the number of blocks of the range.
+//
+// Returns:
+// - uint64: The number of bits of the bitmap.
+func (b *Bitmap) Blocks() uint64 {
+	return b.blocks
+}
+
+// BlockSize returns the number of keys each bit stands for.
+//
+// Returns:
+// - int64: The block size.
+func (b *Bitmap) BlockSize() int64 {
+	return b.blockSize.Int64()
+}
+
+// Set marks a block as searched.
+//
+// Parameters:
+// - block: The index of the block, below Blocks.
+//
+// Returns:
+// - bool: True if the block was not marked yet.
+func (b *Bitmap) Set(block uint64) bool {
+	mask := byte(1) << (block % 8)
+	if b.bits[block/8]&mask != 0 {
+		return false
+	}
+	b.bits[block/8] |= mask
+	b.count++
+	return true
+}
+
+// Test checks whether a block is marked as searched.
+//
+// Parameters:
+// - block: The index of the block, below Blocks.
+//
+// Returns:
+// - bool: True if the block is marked.
+func (b *Bitmap) Test(block uint64) bool {
+	return b.bits[block/8]&(1<<(block%8)) != 0
+}
+
+// Count returns the number of blocks marked as searched.
+//
+// Returns:
+// - uint64: The number of set bits.
+func (b *Bitmap) Count() uint64 {
+	return b.count
+}
+
+// NextUnset returns the first block at or after from that is not marked as searched.
+//
+// Parameters:
+// - from: The index of the first block to look at.
+//
+// Returns:
+// - uint64: The index of the block.
+// - bool: False if every block from there on is marked.
+func (b *Bitmap) NextUnset(from uint64) (uint64, bool) {
+	return b.next(from, true)
+}
+
+// NextSet returns the first block at or after from that is marked as searched.
+//
+// Parameters:
+// - from: The index of the first block to look at.
+//
+// Returns:
+// - uint64: The index of the block.
+// - bool: False if no block from there on is marked.
+func (b *Bitmap) NextSet(from uint64) (uint64, bool) {
+	return b.next(from, false)
+}
+
+// next returns the first block at or after from whose bit is clear, if unset is true, or set otherwise. Whole bytes
+// that cannot hold a match are skipped.
+func (b *Bitmap) next(from uint64, unset bool) (uint64, bool) {
+	skip := byte(0)
+	if unset {
+		skip = 0xff
+	}
+	for block := from; block < b.blocks; {
+		if block%8 == 0 && b.bits[block/8] == skip {
+			block += 8
+			continue
+		}
+		if b.Test(block) != unset {
+			return block, true
+		}
+		block++
+	}
+	return b.blocks, false
+}
+
+// BlockInterval returns the keys of a run of blocks.
+//
+// Parameters:
+// - first: The index of the first block of the run.
+// - last: The index of the last block of the run.
+//
+// Returns:
+// - *Interval: The interval from the first key of the first block to the last key of the last block.
+func (b *Bitmap) BlockInterval(first, last uint64) *Interval {
+	start := new(big.Int).SetUint64(first)
+	start.Mul(start, b.blockSize).Add(start, b.min)
+	end := new(big.Int).SetUint64(last + 1)
+	end.Mul(end, b.blockSize).Add(end, b.min).Sub(end, big.NewInt(1))
+	if end.Cmp(b.max) > 0 {
+		end.Set(b.max)
+	}
+	return &Interval{a: start, b: end}
+}
+
+// blockOf returns the block holding a key of the range.
+func (b *Bitmap) blockOf(key *big.Int) uint64 {
+	block := new(big.Int).Sub(key, b.min)
+	return block.Div(block, b.blockSize).Uint64()
+}
+
+// clip restricts an interval to the range of the bitmap.
+//
+// Returns:
+// - uint64: The block holding the first key of the restricted interval.
+// - uint64: The block holding the last key of the restricted interval.
+// - bool: False if the interval lies outside the range.
+func (b *Bitmap) clip(interval *Interval) (uint64, uint64, bool) {
+	if interval.b.Cmp(b.min) < 0 || interval.a.Cmp(b.max) > 0 {
+		return 0, 0, false
+	}
+	return b.blockOf(maxBigInt(interval.a, b.min)), b.blockOf(minBigInt(interval.b, b.max)), true
+}
+
+// Append marks every block that lies entirely inside an interval as searched. The keys of blocks the interval
+// only partly covers are not recorded.
+//
+// Parameters:
+// - interval: The Interval that was searched.
+func (b *Bitmap) Append(interval *Interval) {
+	first, last, ok := b.clip(interval)
+	if !ok {
+		return
+	}
+	if start, _ := b.BlockInterval(first, first).Get(); start.Cmp(interval.a) < 0 {
+		first++
+	}
+	if _, end := b.BlockInterval(last, last).Get(); end.Cmp(interval.b) > 0 {
+		if last == 0 {
+			return
+		}
+		last--
+	}
+	for block := first; block <= last && first <= last; block++ {
+		b.Set(block)
+	}
+}
+
+// AppendIntervals marks the blocks covered by every interval of an IntervalArray, converting interval progress to
+// the bitmap.
+//
+// Parameters:
+// - intervals: A pointer to the IntervalArray to convert.
+func (b *Bitmap) AppendIntervals(intervals *IntervalArray) {
+	for _, interval := range intervals.intervals() {
+		b.Append(&interval)
+	}
+}
+
+// Intervals converts the bitmap to an IntervalArray, one interval per run of marked blocks.
+//
+// Returns:
+// - *IntervalArray: The searched keys as intervals.
+func (b *Bitmap) Intervals() *IntervalArray {
+	intervals := NewEmptyIntervalArray()
+	b.runs(func(first, last uint64) {
+		intervals.Append(b.BlockInterval(first, last))
+	})
+	return intervals
+}
+
+// IsBitmap reports whether the content of a file is a bitmap.
+//
+// Parameters:
+// - data: The content of the file.
+//
+// Returns:
+// - bool: True if the content starts with the bitmap magic.
+func IsBitmap(data []byte) bool {
+	return bytes.HasPrefix(data, bitmapMagic[:])
+}
+
+// ReadBitmap reads a bitmap file without mapping it and converts it to intervals, so bitmap progress can be turned
+// back into a progress file. The header of the intervals holds the range and the block size of the bitmap.
+//
+// Parameters:
+// - filePath: The path of the bitmap file.
+//
+// Returns:
+// - *IntervalArray: The searched keys as intervals.
+// - error: An error if the file cannot be read or is not a valid bitmap.
+func ReadBitmap(filePath string) (*IntervalArray, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < bitmapHeaderSize {
+		return nil, fmt.Errorf("%s: truncated header", filePath)
+	}
+	if !IsBitmap(data) {
+		return nil, fmt.Errorf("%s: not a bitmap file", filePath)
+	}
+	if data[4] != BitmapVersion {
+		return nil, fmt.Errorf("%s: unsupported bitmap version %d", filePath, data[4])
+	}
+	blockSize := binary.BigEndian.Uint64(data[5:13])
+	min := new(big.Int).SetBytes(data[13:45])
+	max := new(big.Int).SetBytes(data[45:bitmapHeaderSize])
+	if blockSize < 1 || blockSize > 1<<63-1 || min.Cmp(max) > 0 {
+		return nil, fmt.Errorf("%s: invalid bitmap range or block size", filePath)
+	}
+	b := &Bitmap{min: min, max: max, blockSize: new(big.Int).SetUint64(blockSize)}
+	blocks := new(big.Int).Sub(max, min)
+	blocks.Div(blocks, b.blockSize).Add(blocks, big.NewInt(1))
+	if !blocks.IsUint64() || uint64(len(data)-bitmapHeaderSize) != (blocks.Uint64()+7)/8 {
+		return nil, fmt.Errorf("%s: the size does not match the range and block size", filePath)
+	}
+	b.blocks = blocks.Uint64()
+	b.bits = data[bitmapHeaderSize:]
+
+	intervals := b.Intervals()
+	intervals.SetHeader(ProgressHeader{Min: min, Max: max, BlockSize: int64(blockSize)})
+	return intervals, nil
+}
+
+// runs calls visit with the first and last block of every run of marked blocks, in order.
+func (b *Bitmap) runs(visit func(first, last uint64)) {
+	for block := uint64(0); ; {
+		first, ok := b.NextSet(block)
+		if !ok {
+			return
+		}
+		block, _ = b.NextUnset(first)
+		visit(first, block-1)
+	}
+}
+
+// Covers checks whether every block an interval touches is marked as searched.
+//
+// Parameters:
+// - interval: The Interval to check.
+//
+// Returns:
+// - bool: True if the interval lies inside the range and all its blocks are marked.
+func (b *Bitmap) Covers(interval *Interval) bool {
+	if interval.a.Cmp(b.min) < 0 || interval.b.Cmp(b.max) > 0 {
+		return false
+	}
+	first, last, _ := b.clip(interval)
+	unset, ok := b.NextUnset(first)
+	return !ok || unset > last
+}
+
+// FirstUncovered returns the first key at or after from whose block is not marked. Keys outside the range of the
+// bitmap are never marked, so a key before the range is returned as is, and the key after the range is returned when
+// every block from there on is marked.
+//
+// Parameters:
+// - from: The first key to look at.
+//
+// Returns:
+// - *big.Int: The first key not covered by a marked block.
+func (b *Bitmap) FirstUncovered(from *big.Int) *big.Int {
+	if from.Cmp(b.min) < 0 || from.Cmp(b.max) > 0 {
+		return new(big.Int).Set(from)
+	}
+	unset, ok := b.NextUnset(b.blockOf(from))
+	if !ok {
+		return new(big.Int).Add(b.max, big.NewInt(1))
+	}
+	return maxBigInt(b.BlockInterval(unset, unset).a, new(big.Int).Set(from))
+}
+
+// HandleIntervalCollision finds the part of an interval left to search. The interval is widened to the blocks it
+// touches, and if some of them are marked, the first run of unmarked blocks among them is returned instead.
+//
+// Parameters:
+// - interval: The Interval to check and handle collisions for.
+//
+// Returns:
+// - bool: True if every block of the interval is marked or the interval lies outside the range.
+// - Interval: The blocks to search, or the interval itself when there are none.
+func (b *Bitmap) HandleIntervalCollision(interval Interval) (bool, Interval) {
+	first, last, ok := b.clip(&interval)
+	if !ok {
+		return true, interval
+	}
+	unset, ok := b.NextUnset(first)
+	if !ok || unset > last {
+		return true, interval
+	}
+	end, _ := b.NextSet(unset)
+	return false, *b.BlockInterval(unset, min(end-1, last))
+}
+
+// CalculateTotalProgress returns the number of keys in the marked blocks.
+//
+// Returns:
+// - *big.Int: The total progress.
+func (b *Bitmap) CalculateTotalProgress() *big.Int {
+	total := new(big.Int).SetUint64(b.count)
+	total.Mul(total, b.blockSize)
+	if b.Test(b.blocks - 1) {
+		// The last block may be shorter than the others.
+		_, end := b.BlockInterval(b.blocks-1, b.blocks-1).Get()
+		full := new(big.Int).SetUint64(b.blocks)
+		full.Mul(full, b.blockSize).Add(full, b.min).Sub(full, big.NewInt(1))
+		total.Sub(total, full.Sub(full, end))
+	}
+	return total
+}
+
+// Size returns the number of runs of marked blocks, which is the number of intervals the bitmap converts to.
+//
+// Returns:
+// - int: The number of runs.
+func (b *Bitmap) Size() int {
+	size := 0
+	b.runs(func(first, last uint64) { size++ })
+	return size
+}
+
+// Save flushes the mapped bitmap to its file, and copies it to filePath if that is another file.
+//
+// Parameters:
+// - filePath: A string representing the path where the bitmap will be saved.
+//
+// Returns:
+// - bool: True if the file was saved successfully, false otherwise.
+func (b *Bitmap) Save(filePath string) bool {
+	if err := b.mapping.flush(); err != nil {
+		log.Println("Error on flush bitmap file:", err)
+		return false
+	}
+	if filePath == b.path {
+		return true
+	}
+	if err := atomicfile.Write(filePath, b.mapping.data); err != nil {
+		log.Println("Error on write bitmap file:", err)
+		return false
+	}
+	return true
+}
+
+// minBigInt returns the minimum of two big integers.
+//
+// Parameters:
+// - a: The first big integer.
+// - b: The second big integer.
+//
+// Returns:
+// - *big.Int: The minimum of the two big integers.
+func minBigInt(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return a
+	}
+	return b
+}
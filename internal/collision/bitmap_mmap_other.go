@@ -0,0 +1,37 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package collision
+
+import (
+	"io"
+	"os"
+)
+
+// mapping keeps a copy of a file in memory on platforms without memory mapping; flush writes it back.
+type mapping struct {
+	data []byte
+	file *os.File
+}
+
+// mapFile reads the first size bytes of a file into memory.
+func mapFile(file *os.File, size int) (*mapping, error) {
+	data := make([]byte, size)
+	if _, err := file.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &mapping{data: data, file: file}, nil
+}
+
+// flush writes the data back to the file and waits for the write to finish.
+func (m *mapping) flush() error {
+	if _, err := m.file.WriteAt(m.data, 0); err != nil {
+		return err
+	}
+	return m.file.Sync()
+}
+
+// unmap releases the copy.
+func (m *mapping) unmap() error {
+	m.data = nil
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package collision
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mapping is a shared, writable memory mapping of a file.
+type mapping struct {
+	data []byte
+}
+
+// mapFile maps the first size bytes of a file into memory; writes to the data reach the file.
+func mapFile(file *os.File, size int) (*mapping, error) {
+	data, err := syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	return &mapping{data: data}, nil
+}
+
+// flush writes the mapped data back to the file and waits for the write to finish.
+func (m *mapping) flush() error {
+	_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&m.data[0])), uintptr(len(m.data)), syscall.MS_SYNC)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// unmap releases the mapping.
+func (m *mapping) unmap() error {
+	return syscall.Munmap(m.data)
+}
@@ -0,0 +1,50 @@
+//go:build windows
+
+package collision
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mapping is a shared, writable memory mapping of a file.
+type mapping struct {
+	data    []byte
+	file    *os.File
+	handle  syscall.Handle
+	address uintptr
+}
+
+// mapFile maps the first size bytes of a file into memory; writes to the data reach the file.
+func mapFile(file *os.File, size int) (*mapping, error) {
+	handle, err := syscall.CreateFileMapping(syscall.Handle(file.Fd()), nil, syscall.PAGE_READWRITE,
+		uint32(uint64(size)>>32), uint32(size), nil)
+	if err != nil {
+		return nil, os.NewSyscallError("CreateFileMapping", err)
+	}
+	address, err := syscall.MapViewOfFile(handle, syscall.FILE_MAP_WRITE, 0, 0, uintptr(size))
+	if err != nil {
+		syscall.CloseHandle(handle)
+		return nil, os.NewSyscallError("MapViewOfFile", err)
+	}
+	data := unsafe.Slice((*byte)(unsafe.Add(nil, address)), size)
+	return &mapping{data: data, file: file, handle: handle, address: address}, nil
+}
+
+// flush writes the mapped data back to the file and waits for the write to finish.
+func (m *mapping) flush() error {
+	if err := syscall.FlushViewOfFile(m.address, uintptr(len(m.data))); err != nil {
+		return os.NewSyscallError("FlushViewOfFile", err)
+	}
+	return m.file.Sync()
+}
+
+// unmap releases the mapping.
+func (m *mapping) unmap() error {
+	err := syscall.UnmapViewOfFile(m.address)
+	if closeErr := syscall.CloseHandle(m.handle); err == nil {
+		err = closeErr
+	}
+	return err
+}
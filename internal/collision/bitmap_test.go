@@ -0,0 +1,156 @@
+package collision
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestBitmap(t *testing.T, path string, min, max, blockSize int64) *Bitmap {
+	t.Helper()
+	b, _, err := OpenBitmap(path, big.NewInt(min), big.NewInt(max), blockSize)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestBitmap_SetTestCountNextUnset(t *testing.T) {
+	b := openTestBitmap(t, filepath.Join(t.TempDir(), "progress.bitmap"), 0, 999, 10)
+	if b.Blocks() != 100 {
+		t.Fatalf("expected 100, got %d", b.Blocks())
+	}
+	for block := uint64(0); block < 20; block++ {
+		b.Set(block)
+	}
+	if b.Set(5) {
+		t.Errorf("expected block 5 to be set already")
+	}
+	b.Set(21)
+	if !b.Test(21) || b.Test(20) {
+		t.Errorf("expected only block 21 of 20 and 21 to be set")
+	}
+	if b.Count() != 21 {
+		t.Errorf("expected 21, got %d", b.Count())
+	}
+	if block, ok := b.NextUnset(0); !ok || block != 20 {
+		t.Errorf("expected 20, got %d (%v)", block, ok)
+	}
+	if block, ok := b.NextUnset(21); !ok || block != 22 {
+		t.Errorf("expected 22, got %d (%v)", block, ok)
+	}
+	if block, ok := b.NextSet(22); ok {
+		t.Errorf("expected no set block, got %d", block)
+	}
+}
+
+func TestBitmap_AppendRecordsWholeBlocks(t *testing.T) {
+	b := openTestBitmap(t, filepath.Join(t.TempDir(), "progress.bitmap"), 100, 194, 10)
+	b.Append(new(Interval).SetInt(105, 139))
+	if b.Count() != 3 || b.Test(0) || !b.Test(1) || !b.Test(3) {
+		t.Errorf("expected blocks 1 to 3, got %v", b.Intervals())
+	}
+	// The last block holds the 5 keys from 190 to 194.
+	b.Append(new(Interval).SetInt(190, 194))
+	if result := b.CalculateTotalProgress(); result.Cmp(big.NewInt(35)) != 0 {
+		t.Errorf("expected 35, got %v", result)
+	}
+	if !b.Covers(new(Interval).SetInt(110, 139)) || b.Covers(new(Interval).SetInt(110, 140)) {
+		t.Errorf("expected blocks 1 to 3 to cover 110-139 only")
+	}
+	if b.Size() != 2 {
+		t.Errorf("expected 2, got %d", b.Size())
+	}
+}
+
+func TestBitmap_HandleIntervalCollision(t *testing.T) {
+	b := openTestBitmap(t, filepath.Join(t.TempDir(), "progress.bitmap"), 0, 999, 10)
+	b.Append(new(Interval).SetInt(100, 149))
+
+	hasCollision, result := b.HandleIntervalCollision(*new(Interval).SetInt(75, 125))
+	if expected := new(Interval).SetInt(70, 99); hasCollision || !result.Equals(*expected) {
+		t.Errorf("expected %v, got %v, %v", expected, hasCollision, result)
+	}
+	hasCollision, result = b.HandleIntervalCollision(*new(Interval).SetInt(105, 175))
+	if expected := new(Interval).SetInt(150, 179); hasCollision || !result.Equals(*expected) {
+		t.Errorf("expected %v, got %v, %v", expected, hasCollision, result)
+	}
+	if hasCollision, _ := b.HandleIntervalCollision(*new(Interval).SetInt(110, 140)); !hasCollision {
+		t.Errorf("expected a collision")
+	}
+	if hasCollision, _ := b.HandleIntervalCollision(*new(Interval).SetInt(2000, 3000)); !hasCollision {
+		t.Errorf("expected an interval outside the range to be rejected")
+	}
+}
+
+func TestBitmap_ReopenAndConvert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.bitmap")
+	b, created, err := OpenBitmap(path, big.NewInt(0), big.NewInt(9999), 100)
+	if err != nil || !created {
+		t.Fatalf("expected a new bitmap, got %v (%v)", created, err)
+	}
+	intervals := NewIntervalArray([]Interval{*new(Interval).SetInt(0, 999), *new(Interval).SetInt(5050, 7000)})
+	b.AppendIntervals(intervals)
+	if !b.Save(path) || b.Close() != nil {
+		t.Fatalf("expected the bitmap to be saved and closed")
+	}
+
+	b = openTestBitmap(t, path, 0, 9999, 100)
+	expected := NewIntervalArray([]Interval{*new(Interval).SetInt(0, 999), *new(Interval).SetInt(5100, 6999)})
+	if result := b.Intervals(); result.String() != expected.String() {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+
+	if _, _, err := OpenBitmap(path, big.NewInt(0), big.NewInt(9999), 50); err == nil {
+		t.Errorf("expected an error for another block size")
+	}
+	if _, _, err := OpenBitmap(filepath.Join(t.TempDir(), "huge.bitmap"), big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), 64), 1); err == nil {
+		t.Errorf("expected an error for a bitmap larger than %d bytes", MaxBitmapSize)
+	}
+}
+
+func TestReadBitmap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.bitmap")
+	b := openTestBitmap(t, path, 1000, 10999, 100)
+	b.AppendIntervals(NewIntervalArray([]Interval{*new(Interval).SetInt(1000, 1999), *new(Interval).SetInt(10900, 10999)}))
+	if !b.Save(path) || b.Close() != nil {
+		t.Fatalf("expected the bitmap to be saved and closed")
+	}
+
+	intervals, err := ReadBitmap(path)
+	if err != nil {
+		t.Fatalf("expected the bitmap to be read, got %v", err)
+	}
+	expected := NewIntervalArray([]Interval{*new(Interval).SetInt(1000, 1999), *new(Interval).SetInt(10900, 10999)})
+	if intervals.String() != expected.String() {
+		t.Errorf("expected %v, got %v", expected, intervals)
+	}
+	if header := intervals.Header(); !header.Matches(big.NewInt(1000), big.NewInt(10999)) || header.BlockSize != 100 {
+		t.Errorf("expected the range and block size of the bitmap, got %v", header)
+	}
+
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, data[:len(data)-1], 0644)
+	if _, err := ReadBitmap(path); err == nil {
+		t.Errorf("expected an error for a truncated bitmap")
+	}
+}
+
+func TestBitmap_FirstUncovered(t *testing.T) {
+	b := openTestBitmap(t, filepath.Join(t.TempDir(), "progress.bitmap"), 100, 999, 10)
+	b.Append(new(Interval).SetInt(100, 149))
+	b.Append(new(Interval).SetInt(160, 179))
+	for _, test := range []struct{ from, expected int64 }{
+		{50, 50}, {100, 150}, {125, 150}, {155, 155}, {160, 180}, {500, 500}, {1500, 1500},
+	} {
+		if result := b.FirstUncovered(big.NewInt(test.from)); result.Int64() != test.expected {
+			t.Errorf("expected %d, got %v", test.expected, result)
+		}
+	}
+	b.Append(new(Interval).SetInt(180, 999))
+	if result := b.FirstUncovered(big.NewInt(500)); result.Int64() != 1000 {
+		t.Errorf("expected 1000, got %v", result)
+	}
+}
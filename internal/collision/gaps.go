@@ -0,0 +1,95 @@
+package collision
+
+import (
+	"math/big"
+	"slices"
+)
+
+// Gaps returns the uncovered gaps of the range [min, max], largest first; gaps of the same size are ordered by
+// their start.
+//
+// Parameters:
+// - min: The first key of the range.
+// - max: The last key of the range.
+// - limit: The maximum number of gaps returned, or 0 or less to return every gap.
+//
+// Returns:
+// - []Interval: The largest gaps, empty if the intervals cover the whole range.
+func (interArray *IntervalArray) Gaps(min, max *big.Int, limit int) []Interval {
+	from := min
+	if stored := floor(interArray.root, min); stored != nil {
+		from = stored.interval.a
+	}
+	return collectGaps(min, max, limit, func(visit func(interval Interval) bool) {
+		ascend(interArray.root, from, visit)
+	})
+}
+
+// Gaps returns the runs of unmarked blocks in the range [min, max], largest first; gaps of the same size are
+// ordered by their start. The gaps are clipped to [min, max], and keys outside the range of the bitmap are never
+// marked, so they are part of the gaps.
+//
+// Parameters:
+// - min: The first key of the range.
+// - max: The last key of the range.
+// - limit: The maximum number of gaps returned, or 0 or less to return every gap.
+//
+// Returns:
+// - []Interval: The largest gaps, empty if the marked blocks cover the whole range.
+func (b *Bitmap) Gaps(min, max *big.Int, limit int) []Interval {
+	return collectGaps(min, max, limit, func(visit func(interval Interval) bool) {
+		first, _, ok := b.clip(&Interval{a: min, b: max})
+		for ok {
+			var last uint64
+			if first, ok = b.NextSet(first); !ok {
+				return
+			}
+			if last, ok = b.NextUnset(first); !ok {
+				last = b.blocks
+			}
+			if !visit(*b.BlockInterval(first, last-1)) {
+				return
+			}
+			first = last
+		}
+	})
+}
+
+// collectGaps collects the gaps of the range [min, max] left between covered intervals, and sorts them by size.
+//
+// Parameters:
+// - min: The first key of the range.
+// - max: The last key of the range.
+// - limit: The maximum number of gaps returned, or 0 or less to return every gap.
+// - walk: A function that visits the disjoint covered intervals in order, from the one that holds or follows min,
+// until the visit returns false.
+//
+// Returns:
+// - []Interval: The largest gaps.
+func collectGaps(min, max *big.Int, limit int, walk func(visit func(interval Interval) bool)) []Interval {
+	var gaps []Interval
+	next, one := new(big.Int).Set(min), big.NewInt(1)
+	walk(func(interval Interval) bool {
+		if interval.a.Cmp(max) > 0 {
+			return false
+		}
+		if interval.a.Cmp(next) > 0 {
+			gaps = append(gaps, Interval{a: new(big.Int).Set(next), b: new(big.Int).Sub(interval.a, one)})
+		}
+		if interval.b.Cmp(next) >= 0 {
+			next.Add(interval.b, one)
+		}
+		return true
+	})
+	if next.Cmp(max) <= 0 {
+		gaps = append(gaps, Interval{a: next, b: new(big.Int).Set(max)})
+	}
+
+	slices.SortStableFunc(gaps, func(x, y Interval) int {
+		return new(big.Int).Sub(y.b, y.a).Cmp(new(big.Int).Sub(x.b, x.a))
+	})
+	if limit > 0 && len(gaps) > limit {
+		gaps = gaps[:limit]
+	}
+	return gaps
+}
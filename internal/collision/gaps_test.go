@@ -0,0 +1,69 @@
+package collision
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+func TestGaps_SortedBySizeWithinRange(t *testing.T) {
+	intervals := NewIntervalArray([]Interval{
+		*new(Interval).SetInt(0, 99),
+		*new(Interval).SetInt(150, 199),
+		*new(Interval).SetInt(230, 300),
+		*new(Interval).SetInt(400, 409),
+		*new(Interval).SetInt(2000, 3000),
+	})
+	gaps := intervals.Gaps(big.NewInt(50), big.NewInt(1000), 0)
+	expected := []Interval{
+		*new(Interval).SetInt(410, 1000),
+		*new(Interval).SetInt(301, 399),
+		*new(Interval).SetInt(100, 149),
+		*new(Interval).SetInt(200, 229),
+	}
+	if len(gaps) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, gaps)
+	}
+	for i := range expected {
+		if !gaps[i].Equals(expected[i]) {
+			t.Errorf("expected %v, got %v", expected, gaps)
+			break
+		}
+	}
+
+	if gaps := intervals.Gaps(big.NewInt(50), big.NewInt(1000), 2); len(gaps) != 2 || !gaps[1].Equals(expected[1]) {
+		t.Errorf("expected the 2 largest gaps, got %v", gaps)
+	}
+	if gaps := intervals.Gaps(big.NewInt(2100), big.NewInt(2900), 0); len(gaps) != 0 {
+		t.Errorf("expected no gaps, got %v", gaps)
+	}
+	if gaps := NewEmptyIntervalArray().Gaps(big.NewInt(5), big.NewInt(9), 0); len(gaps) != 1 || !gaps[0].Equals(*new(Interval).SetInt(5, 9)) {
+		t.Errorf("expected [5, 9], got %v", gaps)
+	}
+}
+
+func TestGaps_EqualSizesOrderedByStart(t *testing.T) {
+	intervals := NewIntervalArray([]Interval{*new(Interval).SetInt(10, 19), *new(Interval).SetInt(30, 39)})
+	gaps := intervals.Gaps(big.NewInt(0), big.NewInt(49), 0)
+	if len(gaps) != 3 || gaps[0].a.Int64() != 0 || gaps[1].a.Int64() != 20 || gaps[2].a.Int64() != 40 {
+		t.Errorf("expected the gaps at 0, 20 and 40, got %v", gaps)
+	}
+}
+
+func TestBitmap_Gaps(t *testing.T) {
+	b := openTestBitmap(t, filepath.Join(t.TempDir(), "progress.bitmap"), 100, 999, 10)
+	b.Append(new(Interval).SetInt(100, 149))
+	b.Append(new(Interval).SetInt(300, 309))
+	b.Append(new(Interval).SetInt(990, 999))
+	gaps := b.Gaps(big.NewInt(100), big.NewInt(999), 0)
+	expected := []Interval{*new(Interval).SetInt(310, 989), *new(Interval).SetInt(150, 299)}
+	if len(gaps) != 2 || !gaps[0].Equals(expected[0]) || !gaps[1].Equals(expected[1]) {
+		t.Errorf("expected %v, got %v", expected, gaps)
+	}
+
+	gaps = b.Gaps(big.NewInt(50), big.NewInt(305), 0)
+	expected = []Interval{*new(Interval).SetInt(150, 299), *new(Interval).SetInt(50, 99)}
+	if len(gaps) != 2 || !gaps[0].Equals(expected[0]) || !gaps[1].Equals(expected[1]) {
+		t.Errorf("expected %v, got %v", expected, gaps)
+	}
+}
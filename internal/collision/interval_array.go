@@ -6,15 +6,22 @@ import (
 )
 
 // IntervalArray represents a collection of intervals.
+//
+// The intervals are kept in a treap ordered by their start, and every interval is coalesced on insert with the
+// ones it overlaps or touches. The stored intervals are therefore disjoint and never adjacent, and overlap queries,
+// inserts and gap searches take logarithmic time in the number of intervals.
 type IntervalArray struct {
-	data []Interval
+	root   *intervalNode
+	size   int
+	header ProgressHeader
 }
 
 // String returns a string representation of the IntervalArray.
 // It lists all intervals in the array as a formatted string.
 func (ia *IntervalArray) String() string {
-	intervalStrings := make([]string, len(ia.data))
-	for i, interval := range ia.data {
+	intervals := ia.intervals()
+	intervalStrings := make([]string, len(intervals))
+	for i, interval := range intervals {
 		intervalStrings[i] = interval.String()
 	}
 
@@ -26,7 +33,7 @@ func (ia *IntervalArray) String() string {
 // Returns:
 // - int: The size of the IntervalArray.
 func (ia *IntervalArray) Size() int {
-	return len(ia.data)
+	return ia.size
 }
 
 // CalculateTotalProgress computes the total progress represented by the intervals.
@@ -36,14 +43,42 @@ func (ia *IntervalArray) Size() int {
 // - *big.Int: The total progress.
 func (ia *IntervalArray) CalculateTotalProgress() *big.Int {
 	total, one := new(big.Int), big.NewInt(1)
-	for _, interval := range ia.data {
+	for _, interval := range ia.intervals() {
 		total.Add(total, interval.b).Sub(total, interval.a).Add(total, one)
 	}
 	return total
 }
 
+// Header returns the range and block size the intervals are saved with in the binary format.
+//
+// Returns:
+// - ProgressHeader: The header of the IntervalArray.
+func (ia *IntervalArray) Header() ProgressHeader {
+	return ia.header
+}
+
+// SetHeader sets the range and block size the intervals are saved with in the binary format.
+//
+// Parameters:
+// - header: The header of the IntervalArray.
+func (ia *IntervalArray) SetHeader(header ProgressHeader) {
+	ia.header = header
+}
+
+// intervals returns the intervals of the IntervalArray sorted by their start.
+func (ia *IntervalArray) intervals() []Interval {
+	intervals := make([]Interval, 0, ia.size)
+	if first := first(ia.root); first != nil {
+		ascend(ia.root, first.interval.a, func(interval Interval) bool {
+			intervals = append(intervals, interval)
+			return true
+		})
+	}
+	return intervals
+}
+
 // NewIntervalArray creates a new IntervalArray from a slice of intervals.
-// It sorts the intervals by their start values.
+// Overlapping and adjacent intervals are coalesced.
 //
 // Parameters:
 // - intervals: A slice of Interval to initialize the IntervalArray.
@@ -51,10 +86,11 @@ func (ia *IntervalArray) CalculateTotalProgress() *big.Int {
 // Returns:
 // - *IntervalArray: The newly created IntervalArray.
 func NewIntervalArray(intervals []Interval) *IntervalArray {
-	newIntervalsArr := make([]Interval, len(intervals))
-	copy(newIntervalsArr, intervals)
-	SortByStart(newIntervalsArr)
-	return &IntervalArray{data: newIntervalsArr}
+	interArray := NewEmptyIntervalArray()
+	for i := range intervals {
+		interArray.Append(&intervals[i])
+	}
+	return interArray
 }
 
 // NewEmptyIntervalArray creates a new empty IntervalArray.
@@ -65,13 +101,47 @@ func NewEmptyIntervalArray() *IntervalArray {
 	return &IntervalArray{}
 }
 
-// Append adds a new interval to the IntervalArray, inserting it in sorted order.
-// It uses the InsertSorted function to ensure the intervals remain ordered.
+// Append adds a new interval to the IntervalArray, coalescing it with the intervals it overlaps or touches.
 //
 // Parameters:
 // - interval: The Interval to be added.
 func (interArray *IntervalArray) Append(interval *Interval) {
-	interArray.data = InsertSorted(interArray.data, *interval)
+	var change int
+	interArray.root, change = insert(interArray.root, *interval)
+	interArray.size += change
+}
+
+// Covers checks whether an interval lies entirely inside one of the intervals of the IntervalArray.
+//
+// Parameters:
+// - interval: The Interval to check.
+//
+// Returns:
+// - bool: True if the interval is already covered, false otherwise.
+func (interArray *IntervalArray) Covers(interval *Interval) bool {
+	stored := floor(interArray.root, interval.a)
+	return stored != nil && stored.interval.b.Cmp(interval.b) >= 0
+}
+
+// FirstUncovered returns the first key at or after from that no interval of the IntervalArray covers. The intervals
+// are never adjacent, so the key right after the interval holding from is always uncovered.
+//
+// Parameters:
+// - from: The first key to look at.
+//
+// Returns:
+// - *big.Int: The first uncovered key.
+func (interArray *IntervalArray) FirstUncovered(from *big.Int) *big.Int {
+	if stored := floor(interArray.root, from); stored != nil && stored.interval.IsPointOverlap(from) {
+		return new(big.Int).Add(stored.interval.b, big.NewInt(1))
+	}
+	return new(big.Int).Set(from)
+}
+
+// overlaps checks whether an interval shares at least one point with the intervals of the IntervalArray.
+func (interArray *IntervalArray) overlaps(interval *Interval) bool {
+	stored := floor(interArray.root, interval.b)
+	return stored != nil && stored.interval.b.Cmp(interval.a) >= 0
 }
 
 // ResolveCollisions resolves collisions for a given target interval by adjusting its start and end values.
@@ -85,13 +155,13 @@ func (interArray *IntervalArray) Append(interval *Interval) {
 // - bool: True if the adjustment was valid, false otherwise.
 func (interArray *IntervalArray) ResolveCollisions(target Interval) (*Interval, bool) {
 	newInterval := target.Clone()
-	for _, interval := range interArray.data {
-		if interval.IsPointOverlap(newInterval.a) {
-			newInterval.a = new(big.Int).Add(interval.b, big.NewInt(1))
-		} else {
-			newInterval.b = new(big.Int).Sub(interval.a, big.NewInt(1))
-			break
-		}
+	stored := first(interArray.root)
+	if stored != nil && stored.interval.IsPointOverlap(newInterval.a) {
+		newInterval.a = new(big.Int).Add(stored.interval.b, big.NewInt(1))
+		stored = ceiling(interArray.root, newInterval.a)
+	}
+	if stored != nil {
+		newInterval.b = new(big.Int).Sub(stored.interval.a, big.NewInt(1))
 	}
 	isValid := newInterval.a.Cmp(newInterval.b) <= 0
 	return newInterval, isValid
@@ -107,45 +177,20 @@ func (interArray *IntervalArray) ResolveCollisions(target Interval) (*Interval,
 // - bool: True if a collision was resolved, false otherwise.
 // - Interval: The resulting interval after handling collisions.
 func (interArray *IntervalArray) HandleIntervalCollision(interval Interval) (bool, Interval) {
-	hasCollision := HasOverlap(&interval, interArray.data)
+	hasCollision := interArray.overlaps(&interval)
 	if hasCollision {
-		intervals := GetIntervalsBetween(&interval, interArray.data)
-		newInterval, valid := NewIntervalArray(intervals).ResolveCollisions(interval)
-		if valid {
-			newHasCollision := HasOverlap(newInterval, interArray.data)
-			if !newHasCollision {
-				return newHasCollision, *newInterval
-			}
+		newInterval := interval.Clone()
+		if stored := floor(interArray.root, newInterval.a); stored != nil && stored.interval.IsPointOverlap(newInterval.a) {
+			newInterval.a = new(big.Int).Add(stored.interval.b, big.NewInt(1))
 		}
-	}
-	return hasCollision, interval
-}
-
-// Optimize merges overlapping intervals to reduce the total number of intervals.
-// It returns the number of intervals removed during the optimization.
-//
-// Returns:
-// - int: The number of intervals removed.
-func (interArray *IntervalArray) Optimize() int {
-	length := len(interArray.data)
-	intervals := interArray.data
-	rmCount := 0
-
-	i := 0
-	for i < length-1 {
-		interval1, interval2 := intervals[i], intervals[i+1]
-		if new(big.Int).Sub(interval2.a, interval1.b).Cmp(big.NewInt(1)) <= 0 {
-			intervals[i] = *new(Interval).Set(interval1.a, maxBigInt(interval1.b, interval2.b))
-			intervals = append(intervals[:i+1], intervals[i+2:]...)
-			rmCount++
-			length--
-		} else {
-			i++
+		if stored := ceiling(interArray.root, newInterval.a); stored != nil && stored.interval.a.Cmp(interval.b) <= 0 {
+			newInterval.b = new(big.Int).Sub(stored.interval.a, big.NewInt(1))
+		}
+		if newInterval.a.Cmp(newInterval.b) <= 0 && !interArray.overlaps(newInterval) {
+			return false, *newInterval
 		}
 	}
-
-	interArray.data = intervals
-	return rmCount
+	return hasCollision, interval
 }
 
 // maxBigInt returns the maximum of two big integers.
@@ -1,6 +1,7 @@
 package collision
 
 import (
+	"math/big"
 	"testing"
 )
 
@@ -129,3 +130,44 @@ func TestResolveCollisions_IntervalExactlyAtEndBoundary(t *testing.T) {
 		t.Errorf("expected %v, got %v", expected, result)
 	}
 }
+
+func TestCovers_InsideAndAcrossIntervals(t *testing.T) {
+	intervals := NewIntervalArray([]Interval{
+		*new(Interval).SetInt(50, 150),
+		*new(Interval).SetInt(200, 300),
+	})
+	cases := []struct {
+		interval *Interval
+		expected bool
+	}{
+		{new(Interval).SetInt(60, 150), true},
+		{new(Interval).SetInt(200, 200), true},
+		{new(Interval).SetInt(140, 210), false},
+		{new(Interval).SetInt(151, 199), false},
+		{new(Interval).SetInt(250, 301), false},
+	}
+	for _, c := range cases {
+		if result := intervals.Covers(c.interval); result != c.expected {
+			t.Errorf("expected %v, got %v for %v", c.expected, result, c.interval)
+		}
+	}
+}
+
+func TestFirstUncovered_SkipsCoveredKeys(t *testing.T) {
+	intervals := NewIntervalArray([]Interval{
+		*new(Interval).SetInt(0, 99),
+		*new(Interval).SetInt(150, 199),
+		*new(Interval).SetInt(200, 300),
+	})
+	cases := []struct{ from, expected int64 }{
+		{0, 100}, {99, 100}, {100, 100}, {120, 120}, {150, 301}, {250, 301}, {400, 400},
+	}
+	for _, c := range cases {
+		if result := intervals.FirstUncovered(big.NewInt(c.from)); result.Int64() != c.expected {
+			t.Errorf("expected %d, got %v for %d", c.expected, result, c.from)
+		}
+	}
+	if result := NewEmptyIntervalArray().FirstUncovered(big.NewInt(7)); result.Int64() != 7 {
+		t.Errorf("expected 7, got %v", result)
+	}
+}
@@ -0,0 +1,148 @@
+package collision
+
+import (
+	"math/big"
+	"math/rand/v2"
+)
+
+// intervalNode is a node of a treap of disjoint intervals, ordered by their start. The heap order of the random
+// priorities keeps the expected depth logarithmic whatever the order of the inserts.
+type intervalNode struct {
+	interval    Interval
+	priority    uint64
+	left, right *intervalNode
+}
+
+// split splits a treap into the intervals that start before key and the ones that start at or after key.
+func split(node *intervalNode, key *big.Int) (*intervalNode, *intervalNode) {
+	if node == nil {
+		return nil, nil
+	}
+	if node.interval.a.Cmp(key) < 0 {
+		left, right := split(node.right, key)
+		node.right = left
+		return node, right
+	}
+	left, right := split(node.left, key)
+	node.left = right
+	return left, node
+}
+
+// merge joins two treaps, every interval of left starting before every interval of right.
+func merge(left, right *intervalNode) *intervalNode {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.priority > right.priority:
+		left.right = merge(left.right, right)
+		return left
+	default:
+		right.left = merge(left, right.left)
+		return right
+	}
+}
+
+// first returns the node of the first interval of a treap, or nil if the treap is empty.
+func first(node *intervalNode) *intervalNode {
+	for node != nil && node.left != nil {
+		node = node.left
+	}
+	return node
+}
+
+// last returns the node of the last interval of a treap, or nil if the treap is empty.
+func last(node *intervalNode) *intervalNode {
+	for node != nil && node.right != nil {
+		node = node.right
+	}
+	return node
+}
+
+// popLast removes the last interval of a non-empty treap.
+func popLast(node *intervalNode) *intervalNode {
+	if node.right == nil {
+		return node.left
+	}
+	node.right = popLast(node.right)
+	return node
+}
+
+// count returns the number of intervals in a treap.
+func count(node *intervalNode) int {
+	if node == nil {
+		return 0
+	}
+	return 1 + count(node.left) + count(node.right)
+}
+
+// floor returns the node of the last interval that starts at or before key, or nil if there is none.
+func floor(node *intervalNode, key *big.Int) *intervalNode {
+	var found *intervalNode
+	for node != nil {
+		if node.interval.a.Cmp(key) <= 0 {
+			found, node = node, node.right
+		} else {
+			node = node.left
+		}
+	}
+	return found
+}
+
+// ceiling returns the node of the first interval that starts at or after key, or nil if there is none.
+func ceiling(node *intervalNode, key *big.Int) *intervalNode {
+	var found *intervalNode
+	for node != nil {
+		if node.interval.a.Cmp(key) >= 0 {
+			found, node = node, node.left
+		} else {
+			node = node.right
+		}
+	}
+	return found
+}
+
+// ascend calls visit with every interval that starts at or after key, in order, until visit returns false.
+//
+// Returns:
+// - bool: False if visit stopped the walk.
+func ascend(node *intervalNode, key *big.Int, visit func(interval Interval) bool) bool {
+	if node == nil {
+		return true
+	}
+	if node.interval.a.Cmp(key) >= 0 {
+		if !ascend(node.left, key, visit) || !visit(node.interval) {
+			return false
+		}
+	}
+	return ascend(node.right, key, visit)
+}
+
+// insert adds an interval to a treap of disjoint intervals, coalescing it with every interval it overlaps or
+// touches, so the intervals stay disjoint and never adjacent.
+//
+// Returns:
+// - *intervalNode: The root of the treap.
+// - int: The change in the number of intervals, 1 if nothing was coalesced and less otherwise.
+func insert(root *intervalNode, interval Interval) (*intervalNode, int) {
+	one := big.NewInt(1)
+	a, b := interval.a, interval.b
+	change := 1
+
+	left, right := split(root, a)
+	if previous := last(left); previous != nil && new(big.Int).Add(previous.interval.b, one).Cmp(a) >= 0 {
+		a, b = previous.interval.a, maxBigInt(b, previous.interval.b)
+		left = popLast(left)
+		change--
+	}
+
+	middle, right := split(right, new(big.Int).Add(b, big.NewInt(2)))
+	if covered := last(middle); covered != nil {
+		b = maxBigInt(b, covered.interval.b)
+		change -= count(middle)
+	}
+
+	node := &intervalNode{interval: Interval{a: a, b: b}, priority: rand.Uint64()}
+	return merge(merge(left, node), right), change
+}
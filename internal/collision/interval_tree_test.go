@@ -0,0 +1,91 @@
+package collision
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestAppend_CoalescesOverlappingAndAdjacent(t *testing.T) {
+	intervals := NewEmptyIntervalArray()
+	intervals.Append(new(Interval).SetInt(100, 200))
+	intervals.Append(new(Interval).SetInt(300, 400))
+	intervals.Append(new(Interval).SetInt(500, 600))
+	if intervals.Size() != 3 {
+		t.Fatalf("expected 3, got %d", intervals.Size())
+	}
+
+	intervals.Append(new(Interval).SetInt(201, 299))
+	if intervals.Size() != 2 {
+		t.Errorf("expected 2, got %d", intervals.Size())
+	}
+	intervals.Append(new(Interval).SetInt(50, 550))
+	expected := new(Interval).SetInt(50, 600)
+	if result := intervals.intervals(); len(result) != 1 || !result[0].Equals(*expected) {
+		t.Errorf("expected [%v], got %v", expected, result)
+	}
+	if result := intervals.CalculateTotalProgress(); result.Cmp(big.NewInt(551)) != 0 {
+		t.Errorf("expected 551, got %v", result)
+	}
+}
+
+func TestIntervalArray_MatchesBitmap(t *testing.T) {
+	const size = 2000
+	random := rand.New(rand.NewPCG(1, 2))
+	intervals := NewEmptyIntervalArray()
+	var covered [size]bool
+
+	randomInterval := func() (int, int) {
+		a := random.IntN(size - 50)
+		return a, a + random.IntN(50)
+	}
+	for step := 0; step < 400; step++ {
+		a, b := randomInterval()
+		intervals.Append(new(Interval).SetInt(a, b))
+		for i := a; i <= b; i++ {
+			covered[i] = true
+		}
+
+		runs, total := 0, 0
+		for i := range covered {
+			if covered[i] {
+				total++
+				if i == 0 || !covered[i-1] {
+					runs++
+				}
+			}
+		}
+		if intervals.Size() != runs {
+			t.Fatalf("expected %d, got %d", runs, intervals.Size())
+		}
+		if result := intervals.CalculateTotalProgress(); result.Cmp(big.NewInt(int64(total))) != 0 {
+			t.Fatalf("expected %d, got %v", total, result)
+		}
+
+		qa, qb := randomInterval()
+		query := new(Interval).SetInt(qa, qb)
+		some, all := false, true
+		for i := qa; i <= qb; i++ {
+			some = some || covered[i]
+			all = all && covered[i]
+		}
+		if result := intervals.Covers(query); result != all {
+			t.Fatalf("expected %v, got %v for %v", all, result, query)
+		}
+		hasCollision, result := intervals.HandleIntervalCollision(*query)
+		if !some && (hasCollision || !result.Equals(*query)) {
+			t.Fatalf("expected %v unchanged, got %v, %v", query, hasCollision, result)
+		}
+		if some && !hasCollision {
+			ra, rb := result.Get()
+			if ra.Int64() < int64(qa) || rb.Int64() > int64(qb) {
+				t.Fatalf("expected an interval inside %v, got %v", query, result)
+			}
+			for i := ra.Int64(); i <= rb.Int64(); i++ {
+				if covered[i] {
+					t.Fatalf("expected %v to be free, got key %d covered", result, i)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package collision
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+)
+
+// JournalVersion is the version of the journal format written by this package.
+const JournalVersion = 1
+
+// journalMagic starts every journal file.
+var journalMagic = [4]byte{'G', 'K', 'J', 'L'}
+
+// journalHeaderSize is the size of the journal header: magic and version.
+const journalHeaderSize = 4 + 1
+
+// Journal is an append-only file of the work units completed since the progress was last saved. Every unit is
+// written as soon as it finishes, so a crash or a kill only loses the units still running: the next run replays
+// the journal into its progress store before it starts.
+//
+// Each record holds the first key and the length minus one of an interval as varints, followed by the CRC-32 of
+// both, so a record torn by a crash is detected and dropped.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// OpenJournal opens the journal at filePath, replaying the intervals it holds into a store, and prepares it for
+// appending. A journal that ends with a torn record is cut back to its last complete record.
+//
+// Parameters:
+// - filePath: The path of the journal file, created if it does not exist.
+// - store: The store the recorded intervals are appended to.
+//
+// Returns:
+// - *Journal: A pointer to the open journal.
+// - int: The number of intervals replayed.
+// - error: An error if the file cannot be read or written or is not a journal.
+func OpenJournal(filePath string, store Store) (*Journal, int, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := io.ReadAll(file)
+	if err == nil && len(data) == 0 {
+		_, err = file.Write(append(journalMagic[:], JournalVersion))
+		data = nil
+	}
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+
+	replayed, size := 0, int64(journalHeaderSize)
+	if data != nil {
+		if len(data) < journalHeaderSize || !bytes.HasPrefix(data, journalMagic[:]) {
+			file.Close()
+			return nil, 0, fmt.Errorf("%s: not a journal file", filePath)
+		}
+		if data[4] != JournalVersion {
+			file.Close()
+			return nil, 0, fmt.Errorf("%s: unsupported journal version %d", filePath, data[4])
+		}
+		reader := bytes.NewReader(data[journalHeaderSize:])
+		for {
+			interval, err := readJournalRecord(reader)
+			if err != nil {
+				break
+			}
+			store.Append(interval)
+			replayed++
+			size = int64(len(data) - reader.Len())
+		}
+		if err := file.Truncate(size); err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+		if _, err := file.Seek(size, io.SeekStart); err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+	}
+	return &Journal{path: filePath, file: file}, replayed, nil
+}
+
+// readJournalRecord reads one record of a journal.
+func readJournalRecord(reader *bytes.Reader) (*Interval, error) {
+	offset := reader.Size() - int64(reader.Len())
+	start, err := readBigUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	length, err := readBigUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	record := make([]byte, reader.Size()-int64(reader.Len())-offset)
+	reader.ReadAt(record, offset)
+	var checksum [4]byte
+	if _, err := io.ReadFull(reader, checksum[:]); err != nil {
+		return nil, errors.New("truncated record")
+	}
+	if crc32.ChecksumIEEE(record) != binary.BigEndian.Uint32(checksum[:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	return &Interval{a: start, b: length.Add(length, start)}, nil
+}
+
+// Path returns the path of the journal file.
+//
+// Returns:
+// - string: The path of the journal.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Record appends a completed interval to the journal. The record is written at once, so it survives a crash of
+// the program; the operating system writes it to disk shortly after.
+//
+// Parameters:
+// - interval: The Interval whose keys have all been searched.
+//
+// Returns:
+// - error: An error if the record cannot be written.
+func (j *Journal) Record(interval *Interval) error {
+	if interval.a.Sign() < 0 {
+		return fmt.Errorf("negative key %v does not fit the journal", interval.a)
+	}
+	record := appendBigUvarint(nil, interval.a)
+	record = appendBigUvarint(record, new(big.Int).Sub(interval.b, interval.a))
+	record = binary.BigEndian.AppendUint32(record, crc32.ChecksumIEEE(record))
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err := j.file.Write(record)
+	return err
+}
+
+// Clear compacts the journal once the intervals it holds have been saved with the progress, leaving only its
+// header.
+//
+// Returns:
+// - error: An error if the journal cannot be truncated.
+func (j *Journal) Clear() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.file.Truncate(journalHeaderSize); err != nil {
+		return err
+	}
+	if _, err := j.file.Seek(journalHeaderSize, io.SeekStart); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// Close syncs and closes the journal file.
+//
+// Returns:
+// - error: An error if the journal cannot be synced or closed.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	err := j.file.Sync()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package collision
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal_ReplaysRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.journal")
+	journal, replayed, err := OpenJournal(path, NewEmptyIntervalArray())
+	if err != nil || replayed != 0 {
+		t.Fatalf("expected an empty journal, got %d (%v)", replayed, err)
+	}
+	large, _ := new(big.Int).SetString("2a5c3b0e1f0000000", 16)
+	intervals := []*Interval{
+		new(Interval).SetInt(0, 99),
+		new(Interval).SetInt(100, 199),
+		new(Interval).Set(large, new(big.Int).Add(large, big.NewInt(65535))),
+	}
+	for _, interval := range intervals {
+		if err := journal.Record(interval); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	journal.Close()
+
+	store := NewEmptyIntervalArray()
+	journal, replayed, err = OpenJournal(path, store)
+	if err != nil || replayed != 3 {
+		t.Fatalf("expected 3 records, got %d (%v)", replayed, err)
+	}
+	defer journal.Close()
+	expected := NewIntervalArray([]Interval{*intervals[0], *intervals[1], *intervals[2]})
+	if store.String() != expected.String() {
+		t.Errorf("expected %v, got %v", expected, store)
+	}
+}
+
+func TestJournal_DropsTornRecordAndClears(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.journal")
+	journal, _, _ := OpenJournal(path, NewEmptyIntervalArray())
+	journal.Record(new(Interval).SetInt(0, 99))
+	journal.Record(new(Interval).SetInt(1000, 1999))
+	journal.Close()
+
+	// Cut the last record short, as a crash in the middle of a write would.
+	info, _ := os.Stat(path)
+	os.Truncate(path, info.Size()-2)
+	journal, replayed, err := OpenJournal(path, NewEmptyIntervalArray())
+	if err != nil || replayed != 1 {
+		t.Fatalf("expected 1 record, got %d (%v)", replayed, err)
+	}
+	journal.Record(new(Interval).SetInt(200, 299))
+	journal.Close()
+
+	store := NewEmptyIntervalArray()
+	journal, replayed, _ = OpenJournal(path, store)
+	if expected := NewIntervalArray([]Interval{*new(Interval).SetInt(0, 99), *new(Interval).SetInt(200, 299)}); replayed != 2 || store.String() != expected.String() {
+		t.Errorf("expected %v, got %v", expected, store)
+	}
+
+	if err := journal.Clear(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	journal.Close()
+	if _, replayed, _ := OpenJournal(path, NewEmptyIntervalArray()); replayed != 0 {
+		t.Errorf("expected 0, got %d", replayed)
+	}
+}
+
+func TestOpenJournal_RejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.journal")
+	os.WriteFile(path, []byte(`{"Intervals":[]}`), 0644)
+	if _, _, err := OpenJournal(path, NewEmptyIntervalArray()); err == nil {
+		t.Errorf("expected an error for a file that is not a journal")
+	}
+}
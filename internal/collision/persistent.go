@@ -1,7 +1,9 @@
 package collision
 
 import (
+	"GoKeyHunt/internal/utils/atomicfile"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -18,23 +20,44 @@ type intervalsTemp struct {
 	Data []intervalTemp `json:"Intervals"`
 }
 
+// Save writes the intervals to filePath, as JSON if the file has the .json extension and in the binary format
+// otherwise. The file is replaced atomically and the previous one is kept as its backup.
 func (intArr *IntervalArray) Save(filePath string) bool {
-	intervals := intArr.toTempIntervals()
-
-	jsonData, err := json.Marshal(intervals)
+	data, err := intArr.Marshal(FormatOf(filePath))
 	if err != nil {
 		log.Println("Error on Marshal function:", err)
 		return false
 	}
 
-	err = os.WriteFile(filePath, jsonData, 0644)
+	err = atomicfile.Write(filePath, data)
 	if err != nil {
-		log.Println("Error on write json file:", err)
+		log.Println("Error on write progress file:", err)
 		return false
 	}
 	return true
 }
 
+// Marshal encodes the intervals in the given format.
+func (intArr *IntervalArray) Marshal(format Format) ([]byte, error) {
+	if format == FormatBinary {
+		return intArr.marshalBinary()
+	}
+	return json.Marshal(intArr.toTempIntervals())
+}
+
+// Unmarshal decodes intervals written by Marshal, detecting the format from the content.
+func Unmarshal(data []byte) (*IntervalArray, error) {
+	if DetectFormat(data) == FormatBinary {
+		return unmarshalBinary(data)
+	}
+
+	var intervalsTmp intervalsTemp
+	if err := json.Unmarshal(data, &intervalsTmp); err != nil {
+		return nil, err
+	}
+	return toIntervalArray(intervalsTmp), nil
+}
+
 func Read(filePath string) (*IntervalArray, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -47,13 +70,11 @@ func Read(filePath string) (*IntervalArray, error) {
 		return nil, err
 	}
 
-	var intervalsTmp intervalsTemp
-	if err := json.Unmarshal(bytes, &intervalsTmp); err != nil {
-		return nil, err
+	intervalArr, err := Unmarshal(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
 	}
-
-	intervalArr := toIntervalArray(intervalsTmp)
-	return &intervalArr, nil
+	return intervalArr, nil
 }
 
 func ReadOrNew(filePath string) *IntervalArray {
@@ -64,7 +85,7 @@ func ReadOrNew(filePath string) *IntervalArray {
 	return IntervalArr
 }
 
-func toIntervalArray(intervalsTmp intervalsTemp) IntervalArray {
+func toIntervalArray(intervalsTmp intervalsTemp) *IntervalArray {
 	var intervals []Interval
 	for _, intervalTemp := range intervalsTmp.Data {
 		interval, success := new(Interval).SetString(intervalTemp.A, intervalTemp.B, DefaultBase)
@@ -72,14 +93,12 @@ func toIntervalArray(intervalsTmp intervalsTemp) IntervalArray {
 			intervals = append(intervals, *interval)
 		}
 	}
-	SortByStart(intervals)
-	return IntervalArray{data: intervals}
+	return NewIntervalArray(intervals)
 }
 
 func (intArr *IntervalArray) toTempIntervals() intervalsTemp {
-	intArr.Optimize()
-	intervalsTmpArr := make([]intervalTemp, len(intArr.data))
-	for i, interval := range intArr.data {
+	intervalsTmpArr := make([]intervalTemp, intArr.Size())
+	for i, interval := range intArr.intervals() {
 		intervalsTmpArr[i] = intervalTemp{A: interval.a.Text(DefaultBase), B: interval.b.Text(DefaultBase)}
 	}
 	intervals := intervalsTemp{Data: intervalsTmpArr}
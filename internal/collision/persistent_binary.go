@@ -0,0 +1,218 @@
+package collision
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"path/filepath"
+	"strings"
+)
+
+// ProgressFileVersion is the version of the binary progress format written by this package.
+const ProgressFileVersion = 1
+
+// progressMagic starts every binary progress file.
+var progressMagic = [4]byte{'G', 'K', 'P', 'I'}
+
+// Format is an on-disk format of the progress intervals.
+type Format int
+
+const (
+	// FormatJSON is the original format: the base62 bounds of every interval in a JSON document.
+	FormatJSON Format = iota
+	// FormatBinary is the compact format: a header, the delta/varint-encoded intervals and a trailing CRC-32.
+	FormatBinary
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	if f == FormatBinary {
+		return "binary"
+	}
+	return "json"
+}
+
+// FormatOf returns the format a progress file is written in, chosen by its extension: ".json" files are written as
+// JSON and every other file in the binary format. Files are read in whichever format their content is in.
+//
+// Parameters:
+// - filePath: The path of the progress file.
+//
+// Returns:
+// - Format: The format of the file.
+func FormatOf(filePath string) Format {
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		return FormatJSON
+	}
+	return FormatBinary
+}
+
+// DetectFormat returns the format of the content of a progress file.
+//
+// Parameters:
+// - data: The content of the file.
+//
+// Returns:
+// - Format: FormatBinary if the content starts with the binary magic, FormatJSON otherwise.
+func DetectFormat(data []byte) Format {
+	if bytes.HasPrefix(data, progressMagic[:]) {
+		return FormatBinary
+	}
+	return FormatJSON
+}
+
+// ProgressHeader describes the range a binary progress file belongs to.
+//
+// Fields:
+// - Min: The smallest key of the wallet range.
+// - Max: The largest key of the wallet range.
+// - BlockSize: The number of keys the intervals were scheduled in, or 1 for arbitrary intervals.
+type ProgressHeader struct {
+	Min       *big.Int
+	Max       *big.Int
+	BlockSize int64
+}
+
+// Matches reports whether the header belongs to a wallet range. A header without a range matches every range.
+//
+// Parameters:
+// - min: The smallest key of the range.
+// - max: The largest key of the range.
+//
+// Returns:
+// - bool: True if the header has no range or the same range.
+func (h ProgressHeader) Matches(min, max *big.Int) bool {
+	return h.Min == nil || h.Max == nil || (h.Min.Cmp(min) == 0 && h.Max.Cmp(max) == 0)
+}
+
+// marshalBinary encodes the IntervalArray in the binary format: the magic, the version, the range and the block
+// size of the header, the number of intervals, then for every interval the gap since the end of the previous one
+// (or the start of the first one) and its length minus one as varints, and the CRC-32 of everything before it.
+func (ia *IntervalArray) marshalBinary() ([]byte, error) {
+	header := ia.header
+	first, last := header.Min, header.Max
+	if first == nil || last == nil {
+		first, last = new(big.Int), new(big.Int)
+	}
+	if first.Sign() < 0 || last.Sign() < 0 || first.BitLen() > 256 || last.BitLen() > 256 {
+		return nil, errors.New("the range does not fit the binary progress format")
+	}
+	blockSize := max(header.BlockSize, 1)
+
+	buf := make([]byte, 0, 4+1+64+2*binary.MaxVarintLen64+ia.size*16+4)
+	buf = append(buf, progressMagic[:]...)
+	buf = append(buf, ProgressFileVersion)
+	buf = append(buf, first.FillBytes(make([]byte, 32))...)
+	buf = append(buf, last.FillBytes(make([]byte, 32))...)
+	buf = binary.AppendUvarint(buf, uint64(blockSize))
+	buf = binary.AppendUvarint(buf, uint64(ia.size))
+
+	previous, delta, one := new(big.Int), new(big.Int), big.NewInt(1)
+	for i, interval := range ia.intervals() {
+		if interval.a.Sign() < 0 {
+			return nil, fmt.Errorf("negative key %v does not fit the binary progress format", interval.a)
+		}
+		delta.Set(interval.a)
+		if i > 0 {
+			delta.Sub(delta, previous).Sub(delta, one)
+		}
+		buf = appendBigUvarint(buf, delta)
+		buf = appendBigUvarint(buf, delta.Sub(interval.b, interval.a))
+		previous = interval.b
+	}
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// unmarshalBinary decodes an IntervalArray written by marshalBinary, checking its CRC-32 first.
+func unmarshalBinary(data []byte) (*IntervalArray, error) {
+	if len(data) < 4+1+64+2+4 || !bytes.HasPrefix(data, progressMagic[:]) {
+		return nil, errors.New("not a binary progress file")
+	}
+	body, checksum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, errors.New("checksum mismatch")
+	}
+	if body[4] != ProgressFileVersion {
+		return nil, fmt.Errorf("unsupported progress file version %d", body[4])
+	}
+
+	reader := bytes.NewReader(body[5:])
+	var bounds [64]byte
+	reader.Read(bounds[:])
+	header := ProgressHeader{Min: new(big.Int).SetBytes(bounds[:32]), Max: new(big.Int).SetBytes(bounds[32:])}
+	if header.Min.Sign() == 0 && header.Max.Sign() == 0 {
+		header.Min, header.Max = nil, nil
+	}
+	blockSize, err := binary.ReadUvarint(reader)
+	if err != nil || blockSize == 0 || blockSize > 1<<63-1 {
+		return nil, errors.New("invalid block size")
+	}
+	header.BlockSize = int64(blockSize)
+	count, err := binary.ReadUvarint(reader)
+	if err != nil || count > uint64(reader.Len()) {
+		return nil, errors.New("invalid interval count")
+	}
+
+	ia := NewEmptyIntervalArray()
+	ia.header = header
+	previous, one := new(big.Int), big.NewInt(1)
+	for i := uint64(0); i < count; i++ {
+		gap, err := readBigUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("interval %d: %v", i, err)
+		}
+		length, err := readBigUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("interval %d: %v", i, err)
+		}
+		start := gap
+		if i > 0 {
+			start.Add(start, previous).Add(start, one)
+		}
+		end := length.Add(length, start)
+		ia.Append(&Interval{a: start, b: end})
+		previous = end
+	}
+	if reader.Len() != 0 {
+		return nil, errors.New("trailing data after the intervals")
+	}
+	return ia, nil
+}
+
+// appendBigUvarint appends a non-negative big integer as a varint: 7 bits per byte, least significant first, the
+// high bit of every byte but the last set.
+func appendBigUvarint(buf []byte, value *big.Int) []byte {
+	if value.IsUint64() {
+		return binary.AppendUvarint(buf, value.Uint64())
+	}
+	rest := new(big.Int).Set(value)
+	low := new(big.Int)
+	mask := big.NewInt(0x7f)
+	for rest.BitLen() > 7 {
+		buf = append(buf, byte(low.And(rest, mask).Uint64())|0x80)
+		rest.Rsh(rest, 7)
+	}
+	return append(buf, byte(rest.Uint64()))
+}
+
+// readBigUvarint reads a varint written by appendBigUvarint.
+func readBigUvarint(reader *bytes.Reader) (*big.Int, error) {
+	value := new(big.Int)
+	digit := new(big.Int)
+	for shift := uint(0); ; shift += 7 {
+		if shift > 512 {
+			return nil, errors.New("varint overflow")
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, errors.New("truncated varint")
+		}
+		value.Or(value, digit.Lsh(digit.SetUint64(uint64(b&0x7f)), shift))
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+}
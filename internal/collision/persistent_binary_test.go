@@ -0,0 +1,106 @@
+package collision
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testProgress() *IntervalArray {
+	min, _ := new(big.Int).SetString("20000000000000000", 16)
+	max, _ := new(big.Int).SetString("3ffffffffffffffff", 16)
+	intervals := NewEmptyIntervalArray()
+	intervals.SetHeader(ProgressHeader{Min: min, Max: max, BlockSize: 1000})
+	for _, bounds := range [][2]string{
+		{"20000000000000000", "200000000000003e7"},
+		{"2a5c3b0e1f0000000", "2a5c3b0e1f00fffff"},
+		{"3fffffffffffff000", "3ffffffffffffffff"},
+	} {
+		interval, _ := new(Interval).SetString(bounds[0], bounds[1], 16)
+		intervals.Append(interval)
+	}
+	return intervals
+}
+
+func TestBinaryProgress_RoundTrip(t *testing.T) {
+	intervals := testProgress()
+	data, err := intervals.Marshal(FormatBinary)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if DetectFormat(data) != FormatBinary {
+		t.Errorf("expected %v, got %v", FormatBinary, DetectFormat(data))
+	}
+
+	result, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.String() != intervals.String() {
+		t.Errorf("expected %v, got %v", intervals, result)
+	}
+	header, expected := result.Header(), intervals.Header()
+	if header.Min.Cmp(expected.Min) != 0 || header.Max.Cmp(expected.Max) != 0 || header.BlockSize != expected.BlockSize {
+		t.Errorf("expected %v, got %v", expected, header)
+	}
+
+	jsonData, _ := intervals.Marshal(FormatJSON)
+	if len(data) >= len(jsonData) {
+		t.Errorf("expected the binary file to be smaller than %d bytes, got %d", len(jsonData), len(data))
+	}
+}
+
+func TestBinaryProgress_DetectsCorruption(t *testing.T) {
+	data, _ := testProgress().Marshal(FormatBinary)
+	for _, offset := range []int{5, 40, len(data) - 8, len(data) - 1} {
+		corrupted := append([]byte(nil), data...)
+		corrupted[offset] ^= 0x10
+		if _, err := Unmarshal(corrupted); err == nil {
+			t.Errorf("expected an error for a flipped bit at offset %d", offset)
+		}
+	}
+	if _, err := Unmarshal(data[:len(data)-3]); err == nil {
+		t.Errorf("expected an error for a truncated file")
+	}
+}
+
+func TestBinaryProgress_NegativeKeysRejected(t *testing.T) {
+	intervals := NewIntervalArray([]Interval{*new(Interval).SetInt(-10, 10)})
+	if _, err := intervals.Marshal(FormatBinary); err == nil {
+		t.Errorf("expected an error for a negative key")
+	}
+}
+
+func TestRead_DetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+	intervals := testProgress()
+	jsonPath, binaryPath := filepath.Join(dir, "progress.json"), filepath.Join(dir, "progress.bin")
+	if !intervals.Save(jsonPath) || !intervals.Save(binaryPath) {
+		t.Fatalf("expected the files to be saved")
+	}
+
+	for _, path := range []string{jsonPath, binaryPath} {
+		data, _ := os.ReadFile(path)
+		if result := DetectFormat(data); result != FormatOf(path) {
+			t.Errorf("expected %v, got %v for %s", FormatOf(path), result, path)
+		}
+		result, err := Read(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.String() != intervals.String() {
+			t.Errorf("expected %v, got %v", intervals, result)
+		}
+	}
+}
+
+func TestProgressHeader_Matches(t *testing.T) {
+	header := testProgress().Header()
+	if !header.Matches(header.Min, header.Max) || header.Matches(header.Min, big.NewInt(1)) {
+		t.Errorf("expected the header to match its own range only")
+	}
+	if !(ProgressHeader{}).Matches(big.NewInt(1), big.NewInt(2)) {
+		t.Errorf("expected a header without a range to match every range")
+	}
+}
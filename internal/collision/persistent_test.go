@@ -0,0 +1,53 @@
+package collision
+
+import (
+	"GoKeyHunt/internal/utils/atomicfile"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSave_RotatesBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.bin")
+	first := NewIntervalArray([]Interval{*new(Interval).SetInt(0, 99)})
+	second := NewIntervalArray([]Interval{*new(Interval).SetInt(0, 199)})
+	if !first.Save(path) || !second.Save(path) {
+		t.Fatalf("expected the progress to be saved")
+	}
+
+	result, fromBackup, err := atomicfile.ReadWithBackup(path, Read)
+	if err != nil || fromBackup || result.String() != second.String() {
+		t.Errorf("expected %v from the file, got %v (%v, %v)", second, result, fromBackup, err)
+	}
+	backup, err := Read(path + atomicfile.BackupSuffix)
+	if err != nil || backup.String() != first.String() {
+		t.Errorf("expected %v in the backup, got %v (%v)", first, backup, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected the file and its backup only, got %d entries", len(entries))
+	}
+}
+
+func TestReadWithBackup_FallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.bin")
+	if _, _, err := atomicfile.ReadWithBackup(path, Read); !os.IsNotExist(err) {
+		t.Errorf("expected a missing file, got %v", err)
+	}
+
+	first := NewIntervalArray([]Interval{*new(Interval).SetInt(0, 99)})
+	first.Save(path)
+	first.Save(path)
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(path, data, 0644)
+	result, fromBackup, err := atomicfile.ReadWithBackup(path, Read)
+	if err != nil || !fromBackup || result.String() != first.String() {
+		t.Errorf("expected %v from the backup, got %v (%v, %v)", first, result, fromBackup, err)
+	}
+
+	os.Remove(path)
+	if _, fromBackup, err := atomicfile.ReadWithBackup(path, Read); err != nil || !fromBackup {
+		t.Errorf("expected the backup of a missing file, got %v, %v", fromBackup, err)
+	}
+}
@@ -0,0 +1,24 @@
+package collision
+
+import "math/big"
+
+// Store records which keys of a wallet range have already been searched. The scheduler and the end summary work
+// against a Store, so the progress can be kept either as an IntervalArray or as a Bitmap of fixed-size blocks.
+type Store interface {
+	// Append records an interval as searched.
+	Append(interval *Interval)
+	// Covers checks whether every key of an interval has been searched.
+	Covers(interval *Interval) bool
+	// FirstUncovered returns the first key at or after from that has not been searched.
+	FirstUncovered(from *big.Int) *big.Int
+	// Gaps returns the unsearched gaps of a range, largest first, at most limit of them if limit is positive.
+	Gaps(min, max *big.Int, limit int) []Interval
+	// HandleIntervalCollision checks an interval against the searched keys and returns the part left to search.
+	HandleIntervalCollision(interval Interval) (bool, Interval)
+	// CalculateTotalProgress returns the number of searched keys.
+	CalculateTotalProgress() *big.Int
+	// Size returns the number of disjoint intervals the searched keys form.
+	Size() int
+	// Save writes the progress to a file and reports whether it succeeded.
+	Save(filePath string) bool
+}
@@ -11,27 +11,37 @@ import (
 // PrintProgressString prints the progress of a task to the console.
 //
 // This function calculates and displays the progress of a task based on the minimum, maximum, and current values
-// of a given range. It shows the number of keys processed per second, the percentage of completion, the elapsed time,
-// and the estimated time of arrival (ETA) for task completion.
+// of a given range. The range holds maxInt-minInt+1 keys, of which currentInt-minInt have been checked. It shows
+// the number of keys processed per second, the percentage of completion, the elapsed time, and the estimated time
+// of arrival (ETA) for task completion. Once the progress has been checkpointed, it also shows how long ago the last
+// checkpoint was saved.
 //
 // Parameters:
 // - minInt: A *big.Int representing the starting value of the range.
 // - maxInt: A *big.Int representing the ending value of the range.
 // - currentInt: A *big.Int representing the current value in the range.
 // - startTime: A time.Time representing the start time of the task.
-func PrintProgressString(minInt, maxInt, currentInt *big.Int, startTime time.Time) {
+// - lastCheckpoint: A time.Time representing the last checkpoint of the progress, or the zero time if there was none.
+func PrintProgressString(minInt, maxInt, currentInt *big.Int, startTime, lastCheckpoint time.Time) {
 	min, max, current := convertToBigFloat(minInt, maxInt, currentInt)
 	currentF := new(big.Float).Sub(current, min)
 	totalF := new(big.Float).Sub(max, min)
+	totalF.Add(totalF, big.NewFloat(1))
 
 	et := time.Since(startTime).Truncate(time.Second)
-	keysPerSecF := new(big.Float).Quo(currentF, big.NewFloat(et.Seconds()))
+	keysPerSecF := new(big.Float)
+	if currentF.Sign() != 0 {
+		keysPerSecF.Quo(currentF, big.NewFloat(et.Seconds()))
+	}
 	keysPerSec, _ := keysPerSecF.Int64()
 
 	etaStr := getETAStr(totalF, currentF, keysPerSecF)
 	percentage := CalcPercentage(currentF, totalF)
 
 	fmt.Printf("\r%10sk/s | %10f%% | ET: %10v | ETA: %22v", humanize.Comma(keysPerSec), percentage, et, etaStr)
+	if !lastCheckpoint.IsZero() {
+		fmt.Printf(" | CP: %10v ago", time.Since(lastCheckpoint).Truncate(time.Second))
+	}
 }
 
 // convertToBigFloat converts *big.Int values to *big.Float values.
@@ -97,3 +107,24 @@ func CalcPercentage(currentV, totalV *big.Float) float64 {
 	result, _ := percentage.Float64()
 	return result
 }
+
+// PrintKangarooProgress prints the progress of a kangaroo search to the console.
+//
+// This function shows the number of jumps per second, the jumps made so far against the expected total, the number
+// of distinguished points stored and the elapsed time. The expected total is an average, so the percentage may
+// exceed 100%.
+//
+// Parameters:
+// - jumps: The number of jumps made so far.
+// - expected: A *big.Int representing the expected number of jumps.
+// - distinguishedPoints: The number of distinguished points stored.
+// - startTime: A time.Time representing the start time of the search.
+func PrintKangarooProgress(jumps uint64, expected *big.Int, distinguishedPoints int, startTime time.Time) {
+	et := time.Since(startTime).Truncate(time.Second)
+	jumpsF := new(big.Float).SetUint64(jumps)
+	jumpsPerSec, _ := new(big.Float).Quo(jumpsF, big.NewFloat(et.Seconds())).Int64()
+	percentage := CalcPercentage(jumpsF, new(big.Float).SetInt(expected))
+
+	fmt.Printf("\r%10sj/s | %10f%% | DPs: %10s | ET: %10v", humanize.Comma(jumpsPerSec), percentage,
+		humanize.Comma(int64(distinguishedPoints)), et)
+}
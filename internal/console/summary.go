@@ -3,6 +3,7 @@ package console
 import (
 	"GoKeyHunt/internal/app_context"
 	"GoKeyHunt/internal/domain"
+	"GoKeyHunt/internal/permutation"
 	"GoKeyHunt/internal/utils"
 	"fmt"
 	"math/big"
@@ -37,16 +38,14 @@ func PrintSummaryIfVerbose(startOriginal, start, end *big.Int, params domain.Par
 
 // PrintEndSummaryIfVerbose prints the end summary if verbosity is enabled.
 //
-// This function prints a summary of the task completion including elapsed time, JSON size, progress, and whether the target wallet was found.
+// This function prints a summary of the task completion including elapsed time, the number of progress intervals, progress, and whether the target wallet was found.
 //
 // Parameters:
 // - ctx: A pointer to app_context.AppCtx containing application context and configuration.
 // - startTime: The time when the task started.
-// - sizeBeforeOp: The size of the data before the operation.
-// - sizeAfterOp: The size of the data after the operation.
-func PrintEndSummaryIfVerbose(ctx *app_context.AppCtx, startTime time.Time, sizeBeforeOp, sizeAfterOp int) {
+func PrintEndSummaryIfVerbose(ctx *app_context.AppCtx, startTime time.Time) {
 	if ctx.Params.VerboseSummary {
-		printEnd(ctx, startTime, sizeBeforeOp, sizeAfterOp)
+		printEnd(ctx, startTime)
 	}
 }
 
@@ -57,9 +56,7 @@ func PrintEndSummaryIfVerbose(ctx *app_context.AppCtx, startTime time.Time, size
 // Parameters:
 // - ctx: A pointer to app_context.AppCtx containing application context and configuration.
 // - startTime: The time when the task started.
-// - sizeBeforeOp: The size of the data before the operation.
-// - sizeAfterOp: The size of the data after the operation.
-func printEnd(ctx *app_context.AppCtx, startTime time.Time, sizeBeforeOp, sizeAfterOp int) {
+func printEnd(ctx *app_context.AppCtx, startTime time.Time) {
 	intervalProgress := ctx.Intervals.CalculateTotalProgress()
 	start, end := utils.GetWalletStartAndEnd(*ctx.WalletRanges, *ctx.Params)
 	totalProgress := new(big.Int).Sub(end, start)
@@ -70,7 +67,7 @@ func printEnd(ctx *app_context.AppCtx, startTime time.Time, sizeBeforeOp, sizeAf
 			break
 		}
 	}
-	PrintEndSummary(startTime, sizeBeforeOp, sizeAfterOp, intervalProgress, totalProgress, foundTarget)
+	PrintEndSummary(startTime, ctx.Intervals.Size(), intervalProgress, totalProgress, foundTarget)
 }
 
 // PrintSummary prints a detailed summary of the task.
@@ -94,10 +91,35 @@ func PrintSummary(start, end, rng *big.Int, params domain.Parameters, batchCount
 	}
 	fmt.Printf("- From: %s\n", startStr)
 	fmt.Printf("-   To: %s\n", endStr)
+	// Later batches continue where the previous one stopped, so only the first one reports where the run resumed.
+	sequential := !params.Rng && !params.Permutation && params.GapMode == domain.NoGap
+	if sequential && batchCounter == 1 && rng.Cmp(start) > 0 {
+		fmt.Printf("- Resumed at: %s (first key not covered by the progress)\n", rngStr)
+	}
 	fmt.Printf("-\n")
 	fmt.Printf("- Workers count: %s\n", workerCountStr)
+	fmt.Printf("- Group size: %s\n", humanize.Comma(int64(params.GroupSize)))
 	fmt.Printf("- Batch size: %v\n", batchSizeStr)
+	fmt.Printf("- Work unit size: %s\n", humanize.Comma(params.WorkUnitSize))
+	fmt.Printf("- Address mode: %v\n", params.AddressMode)
+	fmt.Printf("- Search mode: %v\n", params.SearchMode)
 	fmt.Printf("- Use RNG start: %v\n", params.Rng)
+	fmt.Printf("- Use block permutation: %v\n", params.Permutation)
+	if params.GapMode != domain.NoGap {
+		fmt.Printf("- Largest gap first: %v\n", params.GapMode)
+	}
+	if params.Rng || params.GapMode == domain.RandomGap {
+		fmt.Printf("- RNG seed: %d\n", params.Seed)
+	}
+	if params.BitmapBlockSize > 0 {
+		fmt.Printf("- Bitmap block size: %s\n", humanize.Comma(params.BitmapBlockSize))
+	}
+	if params.CheckpointMins > 0 {
+		fmt.Printf("- Checkpoint every: %d minutes\n", params.CheckpointMins)
+	}
+	if params.CheckpointKeys > 0 {
+		fmt.Printf("- Checkpoint every: %s keys\n", humanize.Comma(params.CheckpointKeys))
+	}
 	fmt.Printf("- Interval between updates: %s\n", updateIntervalStr)
 	fmt.Printf("-\n")
 	fmt.Printf("- Batch %s/%s\n", batchCounterStr, maxBatchCounterStr)
@@ -128,16 +150,15 @@ func PrintTinySummary(start, end, rng *big.Int, params domain.Parameters, batchC
 
 // PrintEndSummary prints the final summary of the task completion.
 //
-// This function prints details about the elapsed time, JSON size before and after optimization, progress, and whether the target wallet was found.
+// This function prints details about the elapsed time, the number of progress intervals, progress, and whether the target wallet was found.
 //
 // Parameters:
 // - startTime: The time when the task started.
-// - jsonSize: The size of the JSON data before optimization.
-// - optimizedSize: The size of the data after optimization.
+// - intervalCount: The number of disjoint intervals the progress is stored as.
 // - intervalProgress: The progress made in the current interval.
 // - totalProgress: The total progress of the task.
 // - foundTarget: A boolean indicating whether the target wallet was found.
-func PrintEndSummary(startTime time.Time, jsonSize, optimizedSize int, intervalProgress, totalProgress *big.Int, foundTarget bool) {
+func PrintEndSummary(startTime time.Time, intervalCount int, intervalProgress, totalProgress *big.Int, foundTarget bool) {
 	progressPercent := new(big.Float).Quo(new(big.Float).SetInt(intervalProgress), new(big.Float).SetInt(totalProgress))
 	progressPercent.Mul(progressPercent, big.NewFloat(100))
 
@@ -146,14 +167,77 @@ func PrintEndSummary(startTime time.Time, jsonSize, optimizedSize int, intervalP
 
 	fmt.Printf("\n\n%s\n", endSummaryLabel)
 	fmt.Printf("- Elapsed time: %v\n", time.Since(startTime).Truncate(time.Millisecond))
-	fmt.Printf("- Wallet JSON size: %d\n", jsonSize)
-	fmt.Printf("- After optimization: %d\n", optimizedSize)
+	fmt.Printf("- Progress intervals: %d\n", intervalCount)
 	fmt.Printf("- progress: %v%%\n", progressPercent.Text('f', -1))
 	fmt.Printf("- Overall progress: %s/%s\n", intervalProgressStr, totalProgressStr)
 	fmt.Printf("- Wallet was found: %v\n", foundTarget)
 	fmt.Printf("%s\n\n\n", endSummaryLabel)
 }
 
+// PrintBlockOrderIfVerbose prints the state of the block permutation if verbosity is enabled.
+//
+// Parameters:
+// - order: A pointer to the permutation.BlockOrder of the run.
+// - resumed: Whether the order was resumed from a previous run.
+// - params: A domain.Parameters instance containing configuration parameters.
+func PrintBlockOrderIfVerbose(order *permutation.BlockOrder, resumed bool, params domain.Parameters) {
+	if params.VerboseSummary {
+		fmt.Printf("\n\n%s\n", summaryLabel)
+		fmt.Printf("- Permutation seed: %s\n", order.Seed)
+		fmt.Printf("- Resumed: %v\n", resumed)
+		fmt.Printf("- Block %s/%s\n", humanize.BigComma(order.Position()), humanize.BigComma(order.Blocks()))
+		fmt.Printf("%s\n\n\n", summaryLabel)
+	}
+}
+
+// PrintKangarooSummary prints the parameters of a kangaroo search.
+//
+// Parameters:
+// - start: A *big.Int representing the smallest key of the range.
+// - end: A *big.Int representing the largest key of the range.
+// - params: A domain.Parameters instance containing configuration parameters.
+// - dpBits: The number of low x bits that must be zero for a point to be distinguished.
+// - distinguishedPoints: The number of distinguished points resumed from disk.
+// - expectedJumps: A *big.Int representing the expected number of jumps.
+func PrintKangarooSummary(start, end *big.Int, params domain.Parameters, dpBits, distinguishedPoints int, expectedJumps *big.Int) {
+	fmt.Printf("\n\n%s\n", summaryLabel)
+	fmt.Printf("- Target wallet: %d\n", params.TargetWallet)
+	fmt.Printf("- From: %s\n", humanize.BigComma(utils.Clone(start)))
+	fmt.Printf("-   To: %s\n", humanize.BigComma(utils.Clone(end)))
+	fmt.Printf("-\n")
+	fmt.Printf("- Workers count: %s\n", humanize.Comma(int64(params.WorkerCount)))
+	fmt.Printf("- Kangaroos per worker: %s\n", humanize.Comma(int64(params.GroupSize)))
+	fmt.Printf("- Search mode: %v\n", params.SearchMode)
+	fmt.Printf("- Distinguished point bits: %d\n", dpBits)
+	fmt.Printf("- Resumed distinguished points: %s\n", humanize.Comma(int64(distinguishedPoints)))
+	fmt.Printf("- Expected jumps: %s\n", humanize.BigComma(utils.Clone(expectedJumps)))
+	fmt.Printf("%s\n\n\n", summaryLabel)
+}
+
+// PrintBSGSSummary prints the parameters of a baby-step giant-step search.
+//
+// Parameters:
+// - start: A *big.Int representing the smallest key of the range.
+// - end: A *big.Int representing the largest key of the range.
+// - params: A domain.Parameters instance containing configuration parameters.
+// - babySteps: The number of baby steps in the table.
+// - built: Whether the table was built by this run instead of loaded from disk.
+// - giantSteps: The number of giant steps that cover the range.
+func PrintBSGSSummary(start, end *big.Int, params domain.Parameters, babySteps uint64, built bool, giantSteps uint64) {
+	fmt.Printf("\n\n%s\n", summaryLabel)
+	fmt.Printf("- Target wallet: %d\n", params.TargetWallet)
+	fmt.Printf("- From: %s\n", humanize.BigComma(utils.Clone(start)))
+	fmt.Printf("-   To: %s\n", humanize.BigComma(utils.Clone(end)))
+	fmt.Printf("-\n")
+	fmt.Printf("- Workers count: %s\n", humanize.Comma(int64(params.WorkerCount)))
+	fmt.Printf("- Giant steps per batch: %s\n", humanize.Comma(int64(params.GroupSize)))
+	fmt.Printf("- Search mode: %v\n", params.SearchMode)
+	fmt.Printf("- Memory budget: %s\n", humanize.IBytes(uint64(params.MemoryBudget)))
+	fmt.Printf("- Baby steps: %s (built: %v)\n", humanize.Comma(int64(babySteps)), built)
+	fmt.Printf("- Giant steps: %s\n", humanize.Comma(int64(giantSteps)))
+	fmt.Printf("%s\n\n\n", summaryLabel)
+}
+
 // getStrings returns formatted strings for displaying various parameters and counts.
 //
 // This function generates formatted strings for the range, start, end, worker count, batch size, update interval, batch counter, and max batch counter.
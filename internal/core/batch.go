@@ -0,0 +1,232 @@
+package core
+
+import (
+	"GoKeyHunt/internal/utils"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// BatchInverse replaces every value in values with its modular inverse using a single field inversion
+// (Montgomery's trick). The scratch slice must be at least as long as values.
+//
+// All values must be non-zero and have a magnitude of at most 8; the results are normalized.
+//
+// Parameters:
+// - values: The field values to invert in place.
+// - scratch: A slice used to hold the running products.
+func BatchInverse(values, scratch []secp256k1.FieldVal) {
+	if len(values) == 0 {
+		return
+	}
+	scratch[0].Set(&values[0])
+	for i := 1; i < len(values); i++ {
+		scratch[i].Mul2(&scratch[i-1], &values[i])
+	}
+
+	var inverse, current secp256k1.FieldVal
+	inverse.Set(&scratch[len(values)-1]).Inverse()
+	for i := len(values) - 1; i > 0; i-- {
+		current.Set(&values[i])
+		values[i].Mul2(&inverse, &scratch[i-1]).Normalize()
+		inverse.Mul(&current)
+	}
+	values[0].Set(inverse.Normalize())
+}
+
+// BatchWalker walks blocks of consecutive private keys, sharing a single field inversion per block.
+//
+// Each block is computed around a center point C: the points C-iG and C+iG are obtained from a table of
+// precomputed affine points iG, and both additions use the same inverse of x(iG)-x(C). The inversions of
+// the whole block, including the one that brings C itself to affine coordinates, are batched together.
+type BatchWalker struct {
+	size   int
+	half   int
+	start  *big.Int
+	center secp256k1.JacobianPoint
+	step   secp256k1.JacobianPoint
+
+	tableX, tableY []secp256k1.FieldVal
+	inverses       []secp256k1.FieldVal
+	scratch        []secp256k1.FieldVal
+
+	xs, ys []secp256k1.FieldVal
+}
+
+// NewBatchWalker creates a BatchWalker that computes blocks of the given size.
+//
+// Parameters:
+// - size: The number of consecutive keys in each block (must be greater than 0).
+//
+// Returns:
+// - *BatchWalker: A pointer to the newly created BatchWalker.
+func NewBatchWalker(size int) *BatchWalker {
+	half := size / 2
+	w := &BatchWalker{
+		size:     size,
+		half:     half,
+		start:    new(big.Int),
+		tableX:   make([]secp256k1.FieldVal, half+1),
+		tableY:   make([]secp256k1.FieldVal, half+1),
+		inverses: make([]secp256k1.FieldVal, half+1),
+		scratch:  make([]secp256k1.FieldVal, half+1),
+		xs:       make([]secp256k1.FieldVal, size),
+		ys:       make([]secp256k1.FieldVal, size),
+	}
+
+	point := generator
+	for i := 1; i <= half; i++ {
+		affine := point
+		affine.ToAffine()
+		w.tableX[i].Set(&affine.X)
+		w.tableY[i].Set(&affine.Y)
+		secp256k1.AddNonConst(&point, &generator, &point)
+	}
+
+	var scalar secp256k1.ModNScalar
+	secp256k1.ScalarBaseMultNonConst(scalar.SetInt(uint32(size)), &w.step)
+	return w
+}
+
+// Size returns the number of keys in each block.
+//
+// Returns:
+// - int: The block size.
+func (w *BatchWalker) Size() int {
+	return w.size
+}
+
+// Reset positions the walker on the block that begins at the given private key.
+//
+// Parameters:
+// - start: A *big.Int representing the first private key of the block.
+func (w *BatchWalker) Reset(start *big.Int) {
+	w.start.Set(start)
+	centerKey := new(big.Int).Add(start, big.NewInt(int64(w.half)))
+	var scalar secp256k1.ModNScalar
+	scalar.SetByteSlice(centerKey.Bytes())
+	secp256k1.ScalarBaseMultNonConst(&scalar, &w.center)
+}
+
+// Next moves the walker to the block that immediately follows the current one.
+func (w *BatchWalker) Next() {
+	w.start.Add(w.start, big.NewInt(int64(w.size)))
+	secp256k1.AddNonConst(&w.center, &w.step, &w.center)
+}
+
+// Start returns the first private key of the current block. The returned value is owned by the walker.
+//
+// Returns:
+// - *big.Int: The first private key of the current block.
+func (w *BatchWalker) Start() *big.Int {
+	return w.start
+}
+
+// Compute derives the affine public points of every key in the current block.
+func (w *BatchWalker) Compute() {
+	if w.center.Z.IsZero() {
+		w.computeSlow()
+		return
+	}
+
+	// Invert Z and every d_i = x(iG)*Z^2 - X at once. Since x(C) = X/Z^2, the inverse of x(iG)-x(C) is Z^2/d_i.
+	var zz, negX secp256k1.FieldVal
+	zz.SquareVal(&w.center.Z)
+	negX.NegateVal(&w.center.X, 1)
+	w.inverses[0].Set(&w.center.Z)
+	for i := 1; i <= w.half; i++ {
+		w.inverses[i].Mul2(&w.tableX[i], &zz).Add(&negX).Normalize()
+		if w.inverses[i].IsZero() {
+			w.computeSlow()
+			return
+		}
+	}
+	BatchInverse(w.inverses, w.scratch)
+
+	var zInv, x1, y1 secp256k1.FieldVal
+	zInv.Set(&w.inverses[0])
+	zz.SquareVal(&zInv)
+	x1.Mul2(&w.center.X, &zz).Normalize()
+	y1.Mul2(&w.center.Y, zz.Mul(&zInv)).Normalize()
+	zz.SquareVal(&w.center.Z)
+	w.xs[w.half].Set(&x1)
+	w.ys[w.half].Set(&y1)
+
+	var negY1, negSumX, negY2, inverse, lambda secp256k1.FieldVal
+	negY1.NegateVal(&y1, 1)
+	for i := 1; i <= w.half; i++ {
+		inverse.Mul2(&w.inverses[i], &zz)
+		negSumX.Add2(&x1, &w.tableX[i]).Negate(2)
+
+		// C - iG
+		negY2.NegateVal(&w.tableY[i], 1)
+		lambda.Add2(&negY2, &negY1).Mul(&inverse)
+		w.setSum(w.half-i, &lambda, &negSumX, &x1, &negY1)
+
+		// C + iG
+		if w.half+i < w.size {
+			lambda.Add2(&w.tableY[i], &negY1).Mul(&inverse)
+			w.setSum(w.half+i, &lambda, &negSumX, &x1, &negY1)
+		}
+	}
+}
+
+// setSum stores the affine sum x3 = lambda^2 - x1 - x2, y3 = lambda*(x1 - x3) - y1 at the given block index.
+func (w *BatchWalker) setSum(index int, lambda, negSumX, x1, negY1 *secp256k1.FieldVal) {
+	x3, y3 := &w.xs[index], &w.ys[index]
+	x3.SquareVal(lambda).Add(negSumX).Normalize()
+	y3.NegateVal(x3, 1).Add(x1).Mul(lambda).Add(negY1).Normalize()
+}
+
+// computeSlow derives every point of the block with its own scalar multiplication. It is only used when
+// the block touches the point at infinity, where the shared inversion is undefined.
+func (w *BatchWalker) computeSlow() {
+	key := utils.Clone(w.start)
+	var scalar secp256k1.ModNScalar
+	var point secp256k1.JacobianPoint
+	for i := 0; i < w.size; i++ {
+		scalar.SetByteSlice(key.Bytes())
+		secp256k1.ScalarBaseMultNonConst(&scalar, &point)
+		point.ToAffine()
+		w.xs[i].Set(&point.X)
+		w.ys[i].Set(&point.Y)
+		key.Add(key, one)
+	}
+}
+
+// Affine returns the normalized affine coordinates of the public point at the given offset of the current block.
+// Compute must have been called for the current block. The returned values are owned by the walker.
+//
+// Parameters:
+// - offset: The position of the key inside the block.
+//
+// Returns:
+// - *secp256k1.FieldVal: The x coordinate.
+// - *secp256k1.FieldVal: The y coordinate.
+func (w *BatchWalker) Affine(offset int) (*secp256k1.FieldVal, *secp256k1.FieldVal) {
+	return &w.xs[offset], &w.ys[offset]
+}
+
+// PublicKey returns the public key of the key at the given offset of the current block.
+// Compute must have been called for the current block.
+//
+// Parameters:
+// - offset: The position of the key inside the block.
+//
+// Returns:
+// - *secp256k1.PublicKey: The public key in affine coordinates.
+func (w *BatchWalker) PublicKey(offset int) *secp256k1.PublicKey {
+	return secp256k1.NewPublicKey(&w.xs[offset], &w.ys[offset])
+}
+
+// Hash160 writes the Hash160 of the compressed public key at the given offset of the current block into out,
+// without heap allocations. Compute must have been called for the current block.
+//
+// Parameters:
+// - offset: The position of the key inside the block.
+// - out: A pointer to the 20-byte array that receives the Hash160.
+func (w *BatchWalker) Hash160(offset int, out *[20]byte) {
+	var pubKey [utils.CompressedPubKeySize]byte
+	utils.SerializeCompressed(&w.xs[offset], &w.ys[offset], &pubKey)
+	utils.Hash160Compressed(&pubKey, out)
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"GoKeyHunt/internal/utils"
+	"bytes"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// assertBatchMatches walks blockCount blocks from start and compares every hash with the scalar multiplication path.
+func assertBatchMatches(t *testing.T, start *big.Int, size, blockCount int) {
+	t.Helper()
+	walker := NewBatchWalker(size)
+	walker.Reset(start)
+	key := utils.Clone(start)
+	for block := 0; block < blockCount; block++ {
+		walker.Compute()
+		for i := 0; i < size; i++ {
+			expected := utils.CreatePublicHash160(key)
+			var result [20]byte
+			if walker.Hash160(i, &result); !bytes.Equal(result[:], expected) {
+				t.Fatalf("size %d, key %v: expected %x, got %x", size, key, expected, result)
+			}
+			key.Add(key, big.NewInt(1))
+		}
+		walker.Next()
+	}
+}
+
+func TestBatchWalker_BlockSizes(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 8, 33, 64} {
+		assertBatchMatches(t, big.NewInt(1<<20), size, 3)
+	}
+}
+
+func TestBatchWalker_TouchesInfinity(t *testing.T) {
+	assertBatchMatches(t, big.NewInt(0), 16, 2)
+	assertBatchMatches(t, big.NewInt(1), 16, 2)
+}
+
+func TestBatchWalker_RandomRanges(t *testing.T) {
+	minimum, maximum := big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 160)
+	for i := 0; i < 8; i++ {
+		start, err := utils.GenerateRandomNumber(minimum, maximum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertBatchMatches(t, start, 128, 2)
+	}
+}
+
+func TestBatchInverse(t *testing.T) {
+	values := make([]secp256k1.FieldVal, 10)
+	expected := make([]secp256k1.FieldVal, 10)
+	for i := range values {
+		values[i].SetInt(uint16(i*7 + 3))
+		expected[i].Set(&values[i]).Inverse().Normalize()
+	}
+	BatchInverse(values, make([]secp256k1.FieldVal, len(values)))
+	for i := range values {
+		if !values[i].Equals(&expected[i]) {
+			t.Errorf("index %d: expected %v, got %v", i, expected[i], values[i])
+		}
+	}
+}
+
+func BenchmarkBatchWalker(b *testing.B) {
+	for _, size := range []int{64, 512, 4096} {
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			walker := NewBatchWalker(size)
+			walker.Reset(big.NewInt(1 << 40))
+			walker.Compute()
+			var hash [20]byte
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				offset := i % size
+				if offset == 0 {
+					walker.Next()
+					walker.Compute()
+				}
+				walker.Hash160(offset, &hash)
+			}
+		})
+	}
+}
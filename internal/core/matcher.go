@@ -0,0 +1,289 @@
+package core
+
+import (
+	"GoKeyHunt/internal/domain"
+	"GoKeyHunt/internal/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// PublicPoint is a candidate public point shared by every Matcher of a worker.
+//
+// The encodings and hashes of the point are computed on first use and cached until the next Reset, so matchers
+// that need the same derivation (for example the compressed Hash160 for both P2PKH and P2SH-P2WPKH) pay for it once.
+// None of the accessors allocate.
+type PublicPoint struct {
+	x, y *secp256k1.FieldVal
+
+	cached           uint8
+	xBytes           [domain.XOnlyKeySize]byte
+	compressedHash   [20]byte
+	uncompressedHash [20]byte
+}
+
+const (
+	cachedX uint8 = 1 << iota
+	cachedCompressedHash
+	cachedUncompressedHash
+)
+
+// Reset points the PublicPoint at a new affine point and drops every cached derivation.
+// Both coordinates must be normalized and must stay unchanged until the next Reset.
+//
+// Parameters:
+// - x: A pointer to the normalized x coordinate.
+// - y: A pointer to the normalized y coordinate.
+func (p *PublicPoint) Reset(x, y *secp256k1.FieldVal) {
+	p.x, p.y, p.cached = x, y, 0
+}
+
+// Affine returns the coordinates of the point.
+//
+// Returns:
+// - *secp256k1.FieldVal: The x coordinate.
+// - *secp256k1.FieldVal: The y coordinate.
+func (p *PublicPoint) Affine() (*secp256k1.FieldVal, *secp256k1.FieldVal) {
+	return p.x, p.y
+}
+
+// X returns the big-endian bytes of the x coordinate.
+//
+// Returns:
+// - *[32]byte: The x coordinate, owned by the PublicPoint.
+func (p *PublicPoint) X() *[domain.XOnlyKeySize]byte {
+	if p.cached&cachedX == 0 {
+		p.x.PutBytesUnchecked(p.xBytes[:])
+		p.cached |= cachedX
+	}
+	return &p.xBytes
+}
+
+// CompressedHash160 returns the Hash160 of the compressed public key.
+//
+// Returns:
+// - *[20]byte: The Hash160, owned by the PublicPoint.
+func (p *PublicPoint) CompressedHash160() *[20]byte {
+	if p.cached&cachedCompressedHash == 0 {
+		var pubKey [utils.CompressedPubKeySize]byte
+		utils.SerializeCompressed(p.x, p.y, &pubKey)
+		utils.Hash160Compressed(&pubKey, &p.compressedHash)
+		p.cached |= cachedCompressedHash
+	}
+	return &p.compressedHash
+}
+
+// UncompressedHash160 returns the Hash160 of the uncompressed public key.
+//
+// Returns:
+// - *[20]byte: The Hash160, owned by the PublicPoint.
+func (p *PublicPoint) UncompressedHash160() *[20]byte {
+	if p.cached&cachedUncompressedHash == 0 {
+		var pubKey [utils.UncompressedPubKeySize]byte
+		utils.SerializeUncompressed(p.x, p.y, &pubKey)
+		utils.Hash160Uncompressed(&pubKey, &p.uncompressedHash)
+		p.cached |= cachedUncompressedHash
+	}
+	return &p.uncompressedHash
+}
+
+// Matcher decides whether a public point hits one of the wallet addresses of a given kind.
+//
+// Implementations may keep scratch buffers, so a Matcher must not be shared between goroutines.
+type Matcher interface {
+	// Match returns the position of the wallet address hit by the point, or -1 if there is none.
+	Match(point *PublicPoint) int
+	// Info returns the metadata reported with a hit on the wallet address at the given position.
+	Info(wallet int) domain.MatchInfo
+}
+
+// NewMatchers composes the matchers needed by a target set.
+//
+// A matcher is only included when the wallets contain addresses it can hit and, for Hash160 based matchers, when
+// the address mode selects its public key encoding. Nested SegWit addresses always commit to the compressed public
+// key, so like raw public key and Taproot targets they do not depend on the address mode. Keys therefore only pay
+// for the derivations their targets need.
+//
+// Parameters:
+// - wallets: A domain.Wallets instance containing wallet addresses.
+// - mode: The public key encodings hashed for every key.
+//
+// Returns:
+// - []Matcher: The matchers to run on every public point.
+func NewMatchers(wallets domain.Wallets, mode domain.AddressMode) []Matcher {
+	var matchers []Matcher
+	if wallets.HasType(domain.PubKey) {
+		matchers = append(matchers, &pubKeyMatcher{wallets: wallets})
+	}
+	if mode.Compressed() && wallets.HasPubKeyHashes() {
+		matchers = append(matchers, &compressedMatcher{wallets: wallets})
+	}
+	if wallets.HasScriptHashes() {
+		matchers = append(matchers, &nestedSegwitMatcher{wallets: wallets})
+	}
+	if mode.Uncompressed() && wallets.HasUncompressedTargets() {
+		matchers = append(matchers, &uncompressedMatcher{wallets: wallets})
+	}
+	if wallets.HasType(domain.P2TR) {
+		matchers = append(matchers, &taprootMatcher{wallets: wallets})
+	}
+	return matchers
+}
+
+// compressedMatcher matches the Hash160 of the compressed public key against P2PKH and P2WPKH addresses.
+type compressedMatcher struct {
+	wallets domain.Wallets
+}
+
+// Match returns the position of the wallet address hit by the Hash160 of the compressed public key.
+//
+// Parameters:
+// - point: The candidate public point.
+//
+// Returns:
+// - int: The position of the wallet address, or -1 if the point is not a target.
+func (m *compressedMatcher) Match(point *PublicPoint) int {
+	i := m.wallets.FindHash160(point.CompressedHash160())
+	if i == -1 || !m.wallets.Entry(i).Type.PubKeyHash() {
+		return -1
+	}
+	return i
+}
+
+// Info returns the metadata reported with a hit on a P2PKH or P2WPKH address.
+//
+// Parameters:
+// - wallet: The position of the wallet address that was hit.
+//
+// Returns:
+// - domain.MatchInfo: The matcher name and public key format of the hit.
+func (m *compressedMatcher) Info(wallet int) domain.MatchInfo {
+	return domain.MatchInfo{Matcher: "hash160", PubKeyFormat: domain.CompressedMode.String(), Compressed: true}
+}
+
+// uncompressedMatcher matches the Hash160 of the uncompressed public key against P2PKH addresses.
+type uncompressedMatcher struct {
+	wallets domain.Wallets
+}
+
+// Match returns the position of the wallet address hit by the Hash160 of the uncompressed public key.
+//
+// Parameters:
+// - point: The candidate public point.
+//
+// Returns:
+// - int: The position of the wallet address, or -1 if the point is not a target.
+func (m *uncompressedMatcher) Match(point *PublicPoint) int {
+	i := m.wallets.FindHash160(point.UncompressedHash160())
+	if i == -1 || !m.wallets.Entry(i).Type.AcceptsUncompressed() {
+		return -1
+	}
+	return i
+}
+
+// Info returns the metadata reported with a hit on a P2PKH address.
+//
+// Parameters:
+// - wallet: The position of the wallet address that was hit.
+//
+// Returns:
+// - domain.MatchInfo: The matcher name and public key format of the hit.
+func (m *uncompressedMatcher) Info(wallet int) domain.MatchInfo {
+	return domain.MatchInfo{Matcher: "hash160", PubKeyFormat: domain.UncompressedMode.String(), Compressed: false}
+}
+
+// nestedSegwitMatcher matches the script hash of the P2WPKH redeem script of the compressed public key against
+// P2SH-P2WPKH addresses.
+type nestedSegwitMatcher struct {
+	wallets    domain.Wallets
+	scriptHash [20]byte
+}
+
+// Match returns the position of the wallet address hit by the P2WPKH redeem script hash of the compressed public key.
+//
+// Parameters:
+// - point: The candidate public point.
+//
+// Returns:
+// - int: The position of the wallet address, or -1 if the point is not a target.
+func (m *nestedSegwitMatcher) Match(point *PublicPoint) int {
+	utils.Hash160P2WPKHScript(point.CompressedHash160(), &m.scriptHash)
+	i := m.wallets.FindHash160(&m.scriptHash)
+	if i == -1 || !m.wallets.Entry(i).Type.ScriptHash() {
+		return -1
+	}
+	return i
+}
+
+// Info returns the metadata reported with a hit on a P2SH-P2WPKH address.
+//
+// Parameters:
+// - wallet: The position of the wallet address that was hit.
+//
+// Returns:
+// - domain.MatchInfo: The matcher name and public key format of the hit.
+func (m *nestedSegwitMatcher) Info(wallet int) domain.MatchInfo {
+	return domain.MatchInfo{Matcher: "p2sh-p2wpkh", PubKeyFormat: domain.CompressedMode.String(), Compressed: true}
+}
+
+// taprootMatcher matches the BIP-341 tweaked x-only output key against P2TR addresses.
+type taprootMatcher struct {
+	wallets   domain.Wallets
+	outputKey [domain.XOnlyKeySize]byte
+}
+
+// Match returns the position of the wallet address hit by the tweaked output key of the point.
+//
+// Parameters:
+// - point: The candidate public point.
+//
+// Returns:
+// - int: The position of the wallet address, or -1 if the point is not a target.
+func (m *taprootMatcher) Match(point *PublicPoint) int {
+	if !utils.TaprootOutputKey(point.x, point.y, &m.outputKey) {
+		return -1
+	}
+	return m.wallets.FindXOnly(&m.outputKey)
+}
+
+// Info returns the metadata reported with a hit on a P2TR address.
+//
+// Parameters:
+// - wallet: The position of the wallet address that was hit.
+//
+// Returns:
+// - domain.MatchInfo: The matcher name and public key format of the hit.
+func (m *taprootMatcher) Info(wallet int) domain.MatchInfo {
+	return domain.MatchInfo{Matcher: "p2tr", PubKeyFormat: "x-only", Compressed: true}
+}
+
+// pubKeyMatcher matches the public point itself against raw public key targets, without hashing.
+type pubKeyMatcher struct {
+	wallets domain.Wallets
+}
+
+// Match returns the position of the wallet address hit by the point itself.
+//
+// Parameters:
+// - point: The candidate public point.
+//
+// Returns:
+// - int: The position of the wallet address, or -1 if the point is not a target.
+func (m *pubKeyMatcher) Match(point *PublicPoint) int {
+	return m.wallets.FindPubKey(point.X(), point.y.IsOdd())
+}
+
+// Info returns the metadata reported with a hit on a raw public key. It reports the public key form the target was
+// listed in, so the WIF matches the listed key.
+//
+// Parameters:
+// - wallet: The position of the wallet address that was hit.
+//
+// Returns:
+// - domain.MatchInfo: The matcher name and public key format of the hit.
+func (m *pubKeyMatcher) Info(wallet int) domain.MatchInfo {
+	format := domain.CompressedMode
+	if len(m.wallets.Entry(wallet).Address) == 2*utils.UncompressedPubKeySize {
+		format = domain.UncompressedMode
+	}
+	return domain.MatchInfo{Matcher: "pubkey", PubKeyFormat: format.String(), Compressed: format == domain.CompressedMode}
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"GoKeyHunt/internal/domain"
+	"GoKeyHunt/internal/utils"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// matchKey runs the matchers composed for the wallets over the public point of a private key and returns the
+// positions of the wallets that were hit, together with the format reported for each hit.
+func matchKey(wallets domain.Wallets, mode domain.AddressMode, key *big.Int) map[int]string {
+	var x, y secp256k1.FieldVal
+	var point PublicPoint
+	NewKeyWalker(key).Affine(&x, &y)
+	point.Reset(&x, &y)
+	hits := make(map[int]string)
+	for _, matcher := range NewMatchers(wallets, mode) {
+		if wallet := matcher.Match(&point); wallet != -1 {
+			info := matcher.Info(wallet)
+			hits[wallet] = info.Matcher + "/" + info.PubKeyFormat
+		}
+	}
+	return hits
+}
+
+// newTestWallets creates indexed wallets with the given payloads and types.
+func newTestWallets(addresses [][]byte, types ...domain.AddressType) domain.Wallets {
+	wallets := domain.NewWallets(addresses)
+	for _, addressType := range types {
+		wallets.Entries = append(wallets.Entries, domain.WalletEntry{Type: addressType})
+	}
+	return *wallets
+}
+
+func TestMatchers_Compose(t *testing.T) {
+	hash, pubKey := utils.CreatePublicHash160(big.NewInt(1)), utils.CreatePublicKey(big.NewInt(1))
+	tests := []struct {
+		wallets  domain.Wallets
+		mode     domain.AddressMode
+		expected int
+	}{
+		{newTestWallets([][]byte{hash}, domain.P2PKH), domain.CompressedMode, 1},
+		{newTestWallets([][]byte{hash}, domain.P2PKH), domain.BothMode, 2},
+		{newTestWallets([][]byte{hash}, domain.P2WPKH), domain.BothMode, 1},
+		{newTestWallets([][]byte{hash}, domain.P2SHP2WPKH), domain.BothMode, 1},
+		{newTestWallets([][]byte{hash}, domain.P2SHP2WPKH), domain.UncompressedMode, 1},
+		{newTestWallets([][]byte{pubKey}, domain.PubKey), domain.BothMode, 1},
+		{newTestWallets([][]byte{hash, pubKey}, domain.P2PKH, domain.PubKey), domain.CompressedMode, 2},
+	}
+	for i, test := range tests {
+		if matchers := NewMatchers(test.wallets, test.mode); len(matchers) != test.expected {
+			t.Errorf("test %d: expected %d matchers, got %d", i, test.expected, len(matchers))
+		}
+	}
+}
+
+func TestMatchers_Modes(t *testing.T) {
+	wallets := newTestWallets([][]byte{
+		utils.CreatePublicHash160(big.NewInt(5)),
+		utils.CreateUncompressedPublicHash160(big.NewInt(9)),
+	}, domain.P2PKH, domain.P2PKH)
+	tests := []struct {
+		mode     domain.AddressMode
+		key      int64
+		expected map[int]string
+	}{
+		{domain.CompressedMode, 5, map[int]string{0: "hash160/compressed"}},
+		{domain.CompressedMode, 9, map[int]string{}},
+		{domain.UncompressedMode, 5, map[int]string{}},
+		{domain.UncompressedMode, 9, map[int]string{1: "hash160/uncompressed"}},
+		{domain.BothMode, 5, map[int]string{0: "hash160/compressed"}},
+		{domain.BothMode, 9, map[int]string{1: "hash160/uncompressed"}},
+		{domain.BothMode, 7, map[int]string{}},
+	}
+	for _, test := range tests {
+		if hits := matchKey(wallets, test.mode, big.NewInt(test.key)); !reflect.DeepEqual(hits, test.expected) {
+			t.Errorf("mode %v, key %d: expected %v, got %v", test.mode, test.key, test.expected, hits)
+		}
+	}
+}
+
+func TestMatchers_P2WPKHIgnoresUncompressed(t *testing.T) {
+	wallets := newTestWallets([][]byte{
+		utils.CreatePublicHash160(big.NewInt(5)),
+		utils.CreateUncompressedPublicHash160(big.NewInt(9)),
+	}, domain.P2WPKH, domain.P2WPKH)
+	if hits := matchKey(wallets, domain.BothMode, big.NewInt(5)); hits[0] != "hash160/compressed" {
+		t.Errorf("expected a compressed match for a P2WPKH target, got %v", hits)
+	}
+	if hits := matchKey(wallets, domain.BothMode, big.NewInt(9)); len(hits) != 0 {
+		t.Errorf("expected no match, got %v", hits)
+	}
+}
+
+func TestMatchers_NestedSegwit(t *testing.T) {
+	wallets := newTestWallets([][]byte{
+		utils.CreateNestedSegwitHash160(big.NewInt(5)),
+		utils.CreatePublicHash160(big.NewInt(7)),
+	}, domain.P2SHP2WPKH, domain.P2PKH)
+	if hits := matchKey(wallets, domain.CompressedMode, big.NewInt(5)); hits[0] != "p2sh-p2wpkh/compressed" {
+		t.Errorf("expected a nested SegWit match, got %v", hits)
+	}
+	if hits := matchKey(wallets, domain.UncompressedMode, big.NewInt(5)); hits[0] != "p2sh-p2wpkh/compressed" {
+		t.Errorf("expected a nested SegWit match in uncompressed mode, got %v", hits)
+	}
+	if hits := matchKey(wallets, domain.CompressedMode, big.NewInt(7)); hits[1] != "hash160/compressed" {
+		t.Errorf("expected a compressed match, got %v", hits)
+	}
+
+	// A script hash target must not match the plain public key hash that produced it.
+	wallets = newTestWallets([][]byte{utils.CreatePublicHash160(big.NewInt(5))}, domain.P2SHP2WPKH)
+	if hits := matchKey(wallets, domain.CompressedMode, big.NewInt(5)); len(hits) != 0 {
+		t.Errorf("expected no match, got %v", hits)
+	}
+}
+
+func TestMatchers_Taproot(t *testing.T) {
+	wallets := newTestWallets([][]byte{
+		utils.CreatePublicHash160(big.NewInt(5)),
+		utils.CreateTaprootOutputKey(big.NewInt(11)),
+	}, domain.P2PKH, domain.P2TR)
+	tests := []struct {
+		mode     domain.AddressMode
+		key      int64
+		expected map[int]string
+	}{
+		{domain.CompressedMode, 5, map[int]string{0: "hash160/compressed"}},
+		{domain.CompressedMode, 11, map[int]string{1: "p2tr/x-only"}},
+		{domain.UncompressedMode, 11, map[int]string{1: "p2tr/x-only"}},
+		{domain.BothMode, 12, map[int]string{}},
+	}
+	for _, test := range tests {
+		if hits := matchKey(wallets, test.mode, big.NewInt(test.key)); !reflect.DeepEqual(hits, test.expected) {
+			t.Errorf("mode %v, key %d: expected %v, got %v", test.mode, test.key, test.expected, hits)
+		}
+	}
+}
+
+func TestMatchers_PubKey(t *testing.T) {
+	wallets := domain.NewWallets([][]byte{utils.CreatePublicKey(big.NewInt(13))})
+	wallets.Entries = []domain.WalletEntry{{Type: domain.PubKey, Address: string(make([]byte, 130))}}
+	if hits := matchKey(*wallets, domain.BothMode, big.NewInt(13)); hits[0] != "pubkey/uncompressed" {
+		t.Errorf("expected a public key match reported in the listed form, got %v", hits)
+	}
+
+	// The negated key shares the x coordinate but not the public key.
+	negated := new(big.Int).Sub(secp256k1.S256().N, big.NewInt(13))
+	if hits := matchKey(*wallets, domain.BothMode, negated); len(hits) != 0 {
+		t.Errorf("expected no match, got %v", hits)
+	}
+}
+
+func TestPublicPoint_NoAllocations(t *testing.T) {
+	var x, y secp256k1.FieldVal
+	var point PublicPoint
+	NewKeyWalker(big.NewInt(3)).Affine(&x, &y)
+	allocs := testing.AllocsPerRun(100, func() {
+		point.Reset(&x, &y)
+		point.CompressedHash160()
+		point.UncompressedHash160()
+		point.X()
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocations, got %v", allocs)
+	}
+}
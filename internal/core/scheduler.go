@@ -8,19 +8,70 @@ import (
 	"time"
 )
 
-// Scheduler generates private keys within a specified range and sends them to an input channel.
+// WorkUnit represents a block of Count consecutive private keys, beginning at Start, handed to a single Worker.
 //
-// This function iterates from the start key to the end key, incrementing by 1, and sends each private key to the
-// inputChannel. If VerboseProgress is enabled, it periodically prints the progress. The function also tracks the
-// time elapsed and prints the final progress when done.
+// When the Worker finishes checking every key of the unit, it sends the unit back on the done channel so the
+// Scheduler can count completed keys exactly.
+type WorkUnit struct {
+	Start *big.Int
+	Count int64
+
+	done chan<- WorkUnit
+}
+
+// Scheduler splits a range of private keys into work units and sends them to an input channel.
+//
+// This function walks from the start key to the end key in steps of params.WorkUnitSize and sends each work unit to
+// the inputChannel, so every Worker iterates its unit locally. Progress is counted from the units the Workers report
+// as completed, and the function only returns once every unit it sent has been completed. If VerboseProgress is
+// enabled, it periodically prints the progress. The function also tracks the time elapsed and prints the final
+// progress when done.
+//
+// Parameters:
+// - start: A *big.Int representing the starting private key.
+// - end: A *big.Int representing the ending private key.
+// - params: A domain.Parameters instance containing configuration parameters, including UpdateInterval, VerboseProgress and WorkUnitSize.
+// - inputChannel: A send-only channel to which work units are sent.
+func Scheduler(start, end *big.Int, params domain.Parameters, inputChannel chan<- WorkUnit) {
+	Schedule(start, end, params, inputChannel, Hooks{})
+}
+
+// Hooks are the optional callbacks of Schedule.
+//
+// Fields:
+// - Record: Called with every work unit as soon as all its keys have been checked, so the unit can be persisted
+// before the whole range is done.
+// - Checkpoint: Called with the last key of the fully checked prefix of the range every CheckpointMins minutes or
+// CheckpointKeys keys of the parameters. Once it returns, the units completed past that prefix are passed to Record
+// again, so whatever Record writes to may be compacted by the checkpoint.
+// - Stop: A channel that stops the scheduling when closed.
+type Hooks struct {
+	Record     func(unit WorkUnit)
+	Checkpoint func(checked *big.Int)
+	Stop       <-chan struct{}
+}
+
+// Schedule works like Scheduler, calling the hooks as the work units complete.
+//
+// When hooks.Stop is closed, no further unit is sent: the units already in flight are drained, and the function
+// returns the last key of the prefix of the range that has been fully checked, which ends right before the lowest
+// key that was never sent.
 //
 // Parameters:
 // - start: A *big.Int representing the starting private key.
 // - end: A *big.Int representing the ending private key.
-// - params: A domain.Parameters instance containing configuration parameters, including UpdateInterval and VerboseProgress.
-// - inputChannel: A send-only channel to which private keys are sent.
-func Scheduler(start, end *big.Int, params domain.Parameters, inputChannel chan<- *big.Int) {
-	privKey, increment := new(big.Int).Set(start), big.NewInt(1)
+// - params: A domain.Parameters instance containing configuration parameters, including UpdateInterval, VerboseProgress, WorkUnitSize and the checkpoint interval.
+// - inputChannel: A send-only channel to which work units are sent.
+// - hooks: The callbacks of the schedule; any of them may be nil.
+//
+// Returns:
+// - *big.Int: The last key checked, end if the whole range was checked, or start minus one if no key was.
+func Schedule(start, end *big.Int, params domain.Parameters, inputChannel chan<- WorkUnit, hooks Hooks) *big.Int {
+	privKey, unitSize := new(big.Int).Set(start), big.NewInt(params.WorkUnitSize)
+	done := make(chan WorkUnit, params.WorkerCount*2)
+	pending, completed := 0, new(big.Int)
+	prefix := newCheckedPrefix(start)
+	stop, stopped := hooks.Stop, false
 
 	ticker := time.NewTicker(time.Duration(params.UpdateInterval) * time.Second)
 	startTime := time.Now()
@@ -30,13 +81,102 @@ func Scheduler(start, end *big.Int, params domain.Parameters, inputChannel chan<
 		ticker.Stop()
 	}
 
-	for privKey.Cmp(end) <= 0 {
+	var checkpointTimer <-chan time.Time
+	var lastCheckpoint time.Time
+	checkpointKeys, checkpointAt := big.NewInt(params.CheckpointKeys), new(big.Int)
+	if hooks.Checkpoint != nil && params.CheckpointMins > 0 {
+		checkpointTicker := time.NewTicker(time.Duration(params.CheckpointMins) * time.Minute)
+		defer checkpointTicker.Stop()
+		checkpointTimer = checkpointTicker.C
+	}
+	if hooks.Checkpoint != nil && params.CheckpointKeys > 0 {
+		checkpointAt.Add(start, checkpointKeys)
+	}
+	checkpoint := func() {
+		if prefix.next.Cmp(start) > 0 {
+			hooks.Checkpoint(new(big.Int).Sub(prefix.next, big.NewInt(1)))
+			lastCheckpoint = time.Now()
+			for _, unit := range prefix.ahead {
+				if hooks.Record != nil {
+					hooks.Record(unit)
+				}
+			}
+		}
+		if checkpointAt.Sign() != 0 {
+			checkpointAt.Add(prefix.next, checkpointKeys)
+		}
+	}
+
+	for (privKey.Cmp(end) <= 0 && !stopped) || pending > 0 {
+		var unitChannel chan<- WorkUnit
+		var unit WorkUnit
+		if privKey.Cmp(end) <= 0 && !stopped {
+			unitChannel, unit = inputChannel, newWorkUnit(privKey, end, params.WorkUnitSize, done)
+		}
+
 		select {
-		case inputChannel <- utils.Clone(privKey):
-			privKey.Add(privKey, increment)
+		case unitChannel <- unit:
+			privKey.Add(privKey, unitSize)
+			pending++
+		case finished := <-done:
+			completed.Add(completed, big.NewInt(finished.Count))
+			pending--
+			prefix.complete(finished)
+			if hooks.Record != nil {
+				hooks.Record(finished)
+			}
+			if checkpointAt.Sign() != 0 && prefix.next.Cmp(checkpointAt) >= 0 && prefix.next.Cmp(end) <= 0 {
+				checkpoint()
+			}
+		case <-checkpointTimer:
+			checkpoint()
+		case <-stop:
+			stopped, stop = true, nil
 		case <-ticker.C:
-			console.PrintProgressString(start, end, privKey, startTime)
+			console.PrintProgressString(start, end, new(big.Int).Add(start, completed), startTime, lastCheckpoint)
+		}
+	}
+	console.PrintProgressString(start, end, new(big.Int).Add(start, completed), startTime, lastCheckpoint)
+
+	// Every unit sent has been completed, so the checked keys run up to the first key never sent.
+	return prefix.next.Sub(prefix.next, big.NewInt(1))
+}
+
+// checkedPrefix follows the prefix of a range whose keys have all been checked, while the work units complete out
+// of order.
+//
+// Fields:
+// - next: The first key that is not part of the prefix.
+// - ahead: The completed units that start past the prefix, by the text of their first key.
+type checkedPrefix struct {
+	next  *big.Int
+	ahead map[string]WorkUnit
+}
+
+// newCheckedPrefix creates the empty prefix of a range that begins at start.
+func newCheckedPrefix(start *big.Int) *checkedPrefix {
+	return &checkedPrefix{next: utils.Clone(start), ahead: make(map[string]WorkUnit)}
+}
+
+// complete adds a completed work unit, extending the prefix over every unit that now follows it.
+func (p *checkedPrefix) complete(unit WorkUnit) {
+	p.ahead[unit.Start.Text(16)] = unit
+	for {
+		unit, ok := p.ahead[p.next.Text(16)]
+		if !ok {
+			return
 		}
+		delete(p.ahead, p.next.Text(16))
+		p.next.Add(p.next, big.NewInt(unit.Count))
+	}
+}
+
+// newWorkUnit creates the work unit that begins at start, limited to unitSize keys and to the end of the range.
+func newWorkUnit(start, end *big.Int, unitSize int64, done chan<- WorkUnit) WorkUnit {
+	count := unitSize
+	remaining := new(big.Int).Sub(end, start)
+	if remaining.IsInt64() && remaining.Int64() < unitSize {
+		count = remaining.Int64() + 1
 	}
-	console.PrintProgressString(start, end, privKey, startTime)
+	return WorkUnit{Start: utils.Clone(start), Count: count, done: done}
 }
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"GoKeyHunt/internal/domain"
+	"math/big"
+	"testing"
+)
+
+// runScheduler runs the Scheduler over [start, end] with a fake worker and returns the units it received.
+func runScheduler(start, end *big.Int, unitSize int64) []WorkUnit {
+	params := domain.Parameters{WorkerCount: 2, UpdateInterval: 1, WorkUnitSize: unitSize}
+	inputChannel := make(chan WorkUnit)
+	received := make(chan []WorkUnit)
+	go func() {
+		var units []WorkUnit
+		for unit := range inputChannel {
+			units = append(units, unit)
+			completeUnit(unit)
+		}
+		received <- units
+	}()
+	Scheduler(start, end, params, inputChannel)
+	close(inputChannel)
+	return <-received
+}
+
+func TestScheduler_CoversRangeExactly(t *testing.T) {
+	start, end := big.NewInt(1000), big.NewInt(1000+10*64+5)
+	units := runScheduler(start, end, 64)
+
+	next := new(big.Int).Set(start)
+	for _, unit := range units {
+		if unit.Start.Cmp(next) != 0 {
+			t.Fatalf("expected unit starting at %v, got %v", next, unit.Start)
+		}
+		next.Add(next, big.NewInt(unit.Count))
+	}
+	if next.Cmp(new(big.Int).Add(end, big.NewInt(1))) != 0 {
+		t.Errorf("expected coverage up to %v, got %v", end, next)
+	}
+	if len(units) != 11 || units[10].Count != 6 {
+		t.Errorf("expected 11 units with a final unit of 6 keys, got %d units", len(units))
+	}
+}
+
+func TestScheduler_SingleKey(t *testing.T) {
+	units := runScheduler(big.NewInt(7), big.NewInt(7), 64)
+	if len(units) != 1 || units[0].Count != 1 || units[0].Start.Int64() != 7 {
+		t.Errorf("expected a single unit with key 7, got %v", units)
+	}
+}
+
+func TestSchedule_RecordsEveryUnit(t *testing.T) {
+	params := domain.Parameters{WorkerCount: 2, UpdateInterval: 1, WorkUnitSize: 64}
+	inputChannel := make(chan WorkUnit)
+	go func() {
+		for unit := range inputChannel {
+			completeUnit(unit)
+		}
+	}()
+	recorded := new(big.Int)
+	checked := Schedule(big.NewInt(0), big.NewInt(1000), params, inputChannel, Hooks{Record: func(unit WorkUnit) {
+		recorded.Add(recorded, big.NewInt(unit.Count))
+	}})
+	close(inputChannel)
+	if recorded.Cmp(big.NewInt(1001)) != 0 {
+		t.Errorf("expected 1001, got %v", recorded)
+	}
+	if checked.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("expected 1000, got %v", checked)
+	}
+}
+
+func TestSchedule_StopsAfterCheckedPrefix(t *testing.T) {
+	params := domain.Parameters{WorkerCount: 2, UpdateInterval: 1, WorkUnitSize: 64}
+	inputChannel := make(chan WorkUnit)
+	stop := make(chan struct{})
+	go func() {
+		received := 0
+		for unit := range inputChannel {
+			if received++; received == 5 {
+				close(stop)
+			}
+			completeUnit(unit)
+		}
+	}()
+	start := big.NewInt(100)
+	recorded := new(big.Int)
+	checked := Schedule(start, big.NewInt(100000), params, inputChannel, Hooks{Record: func(unit WorkUnit) {
+		recorded.Add(recorded, big.NewInt(unit.Count))
+	}, Stop: stop})
+	close(inputChannel)
+
+	if checked.Cmp(big.NewInt(100000)) >= 0 || checked.Cmp(big.NewInt(100+5*64-1)) < 0 {
+		t.Errorf("expected a stop after at least 5 units, got %v", checked)
+	}
+	if expected := new(big.Int).Sub(checked, start); expected.Add(expected, big.NewInt(1)).Cmp(recorded) != 0 {
+		t.Errorf("expected %v, got %v", expected, recorded)
+	}
+
+	closed := make(chan struct{})
+	close(closed)
+	if checked := Schedule(start, big.NewInt(100000), params, make(chan WorkUnit), Hooks{Stop: closed}); checked.Cmp(big.NewInt(99)) != 0 {
+		t.Errorf("expected 99, got %v", checked)
+	}
+}
+
+func TestSchedule_CheckpointsCheckedPrefix(t *testing.T) {
+	params := domain.Parameters{WorkerCount: 4, UpdateInterval: 1, WorkUnitSize: 64, CheckpointKeys: 640}
+	inputChannel := make(chan WorkUnit, 4)
+	go func() {
+		// Complete the units in pairs, the second one first, so units finish past the checked prefix.
+		for unit := range inputChannel {
+			next, ok := <-inputChannel
+			if ok {
+				completeUnit(next)
+			}
+			completeUnit(unit)
+		}
+	}()
+
+	start, end := big.NewInt(0), big.NewInt(64*100-1)
+	recorded := make(map[int64]bool)
+	var checkpoints []int64
+	Schedule(start, end, params, inputChannel, Hooks{
+		Record: func(unit WorkUnit) {
+			recorded[unit.Start.Int64()] = true
+		},
+		Checkpoint: func(checked *big.Int) {
+			// Like the journal, forget every unit once the prefix is saved.
+			checkpoints = append(checkpoints, checked.Int64())
+			clear(recorded)
+		},
+	})
+	close(inputChannel)
+
+	if len(checkpoints) != 9 {
+		t.Fatalf("expected 9 checkpoints, got %v", checkpoints)
+	}
+	for i, checked := range checkpoints {
+		if checked < int64(i+1)*640-1 || (checked+1)%64 != 0 || (i > 0 && checked <= checkpoints[i-1]) {
+			t.Errorf("expected a growing prefix of whole units past %d, got %v", (i+1)*640-1, checkpoints)
+		}
+	}
+	last := checkpoints[len(checkpoints)-1]
+	for key := last + 1; key <= end.Int64(); key += 64 {
+		if !recorded[key] {
+			t.Errorf("expected the unit at %d to be recorded after the last checkpoint", key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"GoKeyHunt/internal/utils"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// one is the step between two consecutive private keys.
+var one = big.NewInt(1)
+
+// generator is the secp256k1 base point G in Jacobian coordinates, computed once as 1*G.
+var generator = func() secp256k1.JacobianPoint {
+	var k secp256k1.ModNScalar
+	var g secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(k.SetInt(1), &g)
+	return g
+}()
+
+// KeyWalker walks consecutive private keys while keeping track of the matching public point.
+//
+// The point of the starting key is computed once with a full scalar multiplication. Every call to Next
+// moves to the following key by adding G to the current point, which is far cheaper than deriving each
+// public key from scratch.
+type KeyWalker struct {
+	key   *big.Int
+	point secp256k1.JacobianPoint
+}
+
+// NewKeyWalker creates a KeyWalker positioned at the given private key.
+//
+// Parameters:
+// - start: A *big.Int representing the first private key of the walk.
+//
+// Returns:
+// - *KeyWalker: A pointer to the newly created KeyWalker.
+func NewKeyWalker(start *big.Int) *KeyWalker {
+	walker := &KeyWalker{key: new(big.Int)}
+	walker.Reset(start)
+	return walker
+}
+
+// Reset moves the walker to a new private key, recomputing its public point with a scalar multiplication.
+//
+// Parameters:
+// - start: A *big.Int representing the new current private key.
+func (w *KeyWalker) Reset(start *big.Int) {
+	var scalar secp256k1.ModNScalar
+	scalar.SetByteSlice(start.Bytes())
+	secp256k1.ScalarBaseMultNonConst(&scalar, &w.point)
+	w.key.Set(start)
+}
+
+// Next advances the walker to the following private key by adding G to the current point.
+func (w *KeyWalker) Next() {
+	secp256k1.AddNonConst(&w.point, &generator, &w.point)
+	w.key.Add(w.key, one)
+}
+
+// Key returns the current private key. The returned value is owned by the walker and changes on Next.
+//
+// Returns:
+// - *big.Int: The current private key.
+func (w *KeyWalker) Key() *big.Int {
+	return w.key
+}
+
+// PublicKey returns the public key of the current private key.
+//
+// Returns:
+// - *secp256k1.PublicKey: The public key in affine coordinates.
+func (w *KeyWalker) PublicKey() *secp256k1.PublicKey {
+	affine := w.point
+	affine.ToAffine()
+	return secp256k1.NewPublicKey(&affine.X, &affine.Y)
+}
+
+// Affine writes the normalized affine coordinates of the current public point into x and y.
+//
+// Parameters:
+// - x: A pointer to the field value that receives the x coordinate.
+// - y: A pointer to the field value that receives the y coordinate.
+func (w *KeyWalker) Affine(x, y *secp256k1.FieldVal) {
+	affine := w.point
+	affine.ToAffine()
+	x.Set(&affine.X)
+	y.Set(&affine.Y)
+}
+
+// Hash160 writes the Hash160 of the compressed public key of the current private key into out, without heap allocations.
+//
+// Parameters:
+// - out: A pointer to the 20-byte array that receives the Hash160.
+func (w *KeyWalker) Hash160(out *[20]byte) {
+	var x, y secp256k1.FieldVal
+	w.Affine(&x, &y)
+	var pubKey [utils.CompressedPubKeySize]byte
+	utils.SerializeCompressed(&x, &y, &pubKey)
+	utils.Hash160Compressed(&pubKey, out)
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"GoKeyHunt/internal/utils"
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// assertWalkMatches walks count keys from start and compares every hash with the scalar multiplication path.
+func assertWalkMatches(t *testing.T, start *big.Int, count int) {
+	t.Helper()
+	walker := NewKeyWalker(start)
+	key := utils.Clone(start)
+	for i := 0; i < count; i++ {
+		if walker.Key().Cmp(key) != 0 {
+			t.Fatalf("expected key %v, got %v", key, walker.Key())
+		}
+		expected := utils.CreatePublicHash160(key)
+		var result [20]byte
+		if walker.Hash160(&result); !bytes.Equal(result[:], expected) {
+			t.Fatalf("key %v: expected %x, got %x", key, expected, result)
+		}
+		walker.Next()
+		key.Add(key, big.NewInt(1))
+	}
+}
+
+func TestKeyWalker_FromZero(t *testing.T) {
+	assertWalkMatches(t, big.NewInt(0), 64)
+}
+
+func TestKeyWalker_RandomRanges(t *testing.T) {
+	minimum, maximum := big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 160)
+	for i := 0; i < 16; i++ {
+		start, err := utils.GenerateRandomNumber(minimum, maximum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertWalkMatches(t, start, 256)
+	}
+}
+
+func TestKeyWalker_Reset(t *testing.T) {
+	walker := NewKeyWalker(big.NewInt(10))
+	walker.Next()
+	walker.Reset(big.NewInt(1000))
+	expected := utils.CreatePublicHash160(big.NewInt(1000))
+	var result [20]byte
+	if walker.Hash160(&result); !bytes.Equal(result[:], expected) {
+		t.Errorf("expected %x, got %x", expected, result)
+	}
+}
+
+func BenchmarkScalarMultPerKey(b *testing.B) {
+	key := big.NewInt(1 << 40)
+	for i := 0; i < b.N; i++ {
+		utils.CreatePublicHash160(key)
+		key.Add(key, big.NewInt(1))
+	}
+}
+
+func BenchmarkKeyWalker(b *testing.B) {
+	walker := NewKeyWalker(big.NewInt(1 << 40))
+	var hash [20]byte
+	for i := 0; i < b.N; i++ {
+		walker.Hash160(&hash)
+		walker.Next()
+	}
+}
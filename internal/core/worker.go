@@ -5,47 +5,110 @@ import (
 	"GoKeyHunt/internal/utils"
 	"math/big"
 	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
 // Worker is a function that searches for a private key that matches a wallet address.
 //
-// This function listens on the privKeyChan for big.Int private keys. For each key, it generates the corresponding
-// wallet address and checks if it exists in the provided wallets. If a match is found, the private key is sent
-// to the resultChan.
+// This function listens on the workUnitChan for work units. For each unit, it derives the public keys incrementally
+// and runs every Matcher composed from the wallets and params.AddressMode over each public point.
+// If a match is found, the private key, the matched wallet and the matcher metadata are sent to the resultChan. Once every key of
+// the unit has been checked, the unit is reported back as completed. When params.GroupSize is greater than 1, keys are
+// walked in blocks that share a single modular inversion; otherwise each key is walked on its own.
 //
 // Parameters:
 // - wallets: A domain.Wallets instance containing wallet addresses.
-// - privKeyChan: A receive-only channel from which big.Int private keys are received.
-// - resultChan: A send-only channel to which matching big.Int private keys are sent.
+// - params: A domain.Parameters instance containing configuration parameters, including GroupSize and AddressMode.
+// - workUnitChan: A receive-only channel from which work units are received.
+// - resultChan: A send-only channel to which matches are sent.
 // - wg: A pointer to a sync.WaitGroup that is decremented when the function completes.
-func Worker(wallets domain.Wallets, privKeyChan <-chan *big.Int, resultChan chan<- *big.Int, wg *sync.WaitGroup) {
+func Worker(wallets domain.Wallets, params domain.Parameters, workUnitChan <-chan WorkUnit, resultChan chan<- domain.Match, wg *sync.WaitGroup) {
 	defer wg.Done()
-	for privKeyInt := range privKeyChan {
-		address := utils.CreatePublicHash160(privKeyInt)
-		if utils.Contains(wallets.Addresses, address) {
-			resultChan <- privKeyInt
+	matchers := NewMatchers(wallets, params.AddressMode)
+	if params.GroupSize > 1 {
+		walker := NewBatchWalker(params.GroupSize)
+		for unit := range workUnitChan {
+			batchWalk(matchers, walker, unit, resultChan)
+			completeUnit(unit)
+		}
+	} else {
+		walker := NewKeyWalker(big.NewInt(0))
+		for unit := range workUnitChan {
+			sequentialWalk(matchers, walker, unit, resultChan)
+			completeUnit(unit)
+		}
+	}
+}
+
+// sequentialWalk checks every key of a work unit one key at a time, adding G to the previous point.
+func sequentialWalk(matchers []Matcher, walker *KeyWalker, unit WorkUnit, resultChan chan<- domain.Match) {
+	var x, y secp256k1.FieldVal
+	var point PublicPoint
+	walker.Reset(unit.Start)
+	for i := int64(0); i < unit.Count; i++ {
+		walker.Affine(&x, &y)
+		point.Reset(&x, &y)
+		for _, matcher := range matchers {
+			if wallet := matcher.Match(&point); wallet != -1 {
+				sendMatch(walker.Key(), wallet, matcher, resultChan)
+			}
 		}
+		walker.Next()
+	}
+}
+
+// batchWalk checks every key of a work unit in blocks computed by the BatchWalker.
+func batchWalk(matchers []Matcher, walker *BatchWalker, unit WorkUnit, resultChan chan<- domain.Match) {
+	var point PublicPoint
+	walker.Reset(unit.Start)
+	for remaining := unit.Count; remaining > 0; {
+		walker.Compute()
+		count := int(min(remaining, int64(walker.Size())))
+		for i := 0; i < count; i++ {
+			point.Reset(walker.Affine(i))
+			for _, matcher := range matchers {
+				if wallet := matcher.Match(&point); wallet != -1 {
+					key := new(big.Int).Add(walker.Start(), big.NewInt(int64(i)))
+					sendMatch(key, wallet, matcher, resultChan)
+				}
+			}
+		}
+		remaining -= int64(count)
+		walker.Next()
+	}
+}
+
+// sendMatch sends the match of a key on a wallet address, together with the metadata of the matcher that hit it.
+func sendMatch(key *big.Int, wallet int, matcher Matcher, resultChan chan<- domain.Match) {
+	resultChan <- domain.Match{Key: utils.Clone(key), Wallet: wallet, Info: matcher.Info(wallet)}
+}
+
+// completeUnit reports a fully checked work unit back to its Scheduler.
+func completeUnit(unit WorkUnit) {
+	if unit.done != nil {
+		unit.done <- unit
 	}
 }
 
 // WorkersStartUp initializes and starts multiple Worker goroutines.
 //
 // This function spawns a number of Worker goroutines specified by params.WorkerCount. Each Worker listens on the
-// inputChannel for big.Int private keys and sends matching keys to the outputChannel. The function ensures that
+// inputChannel for work units and sends matches to the outputChannel. The function ensures that
 // the sync.WaitGroup is properly managed by incrementing the counter before starting the Workers and decrementing
 // it after they are done.
 //
 // Parameters:
-// - params: A domain.Parameters instance containing configuration parameters, including WorkerCount.
+// - params: A domain.Parameters instance containing configuration parameters, including WorkerCount, GroupSize and AddressMode.
 // - wallets: A domain.Wallets instance containing wallet addresses.
-// - inputChannel: A channel from which big.Int private keys are received by Workers.
-// - outputChannel: A channel to which matching big.Int private keys are sent by Workers.
+// - inputChannel: A channel from which work units are received by Workers.
+// - outputChannel: A channel to which matches are sent by Workers.
 // - wg: A pointer to a sync.WaitGroup that tracks the completion of Worker goroutines.
-func WorkersStartUp(params domain.Parameters, wallets domain.Wallets, inputChannel chan *big.Int, outputChannel chan *big.Int, wg *sync.WaitGroup) {
+func WorkersStartUp(params domain.Parameters, wallets domain.Wallets, inputChannel chan WorkUnit, outputChannel chan domain.Match, wg *sync.WaitGroup) {
 	defer wg.Done()
 	wg.Add(params.WorkerCount)
 
 	for i := 0; i < params.WorkerCount; i++ {
-		go Worker(wallets, inputChannel, outputChannel, wg)
+		go Worker(wallets, params, inputChannel, outputChannel, wg)
 	}
 }
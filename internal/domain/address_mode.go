@@ -0,0 +1,54 @@
+package domain
+
+import "fmt"
+
+// AddressMode selects which public key encodings are hashed for every candidate private key.
+type AddressMode int
+
+const (
+	// CompressedMode hashes only the 33-byte compressed public key.
+	CompressedMode AddressMode = iota
+	// UncompressedMode hashes only the 65-byte uncompressed public key.
+	UncompressedMode
+	// BothMode hashes both encodings, paying for one EC operation and two hashes per key.
+	BothMode
+)
+
+// addressModeNames maps every AddressMode to its command-line name.
+var addressModeNames = map[AddressMode]string{
+	CompressedMode:   "compressed",
+	UncompressedMode: "uncompressed",
+	BothMode:         "both",
+}
+
+// ParseAddressMode converts a command-line name into an AddressMode.
+//
+// Parameters:
+// - name: The name of the mode: "compressed", "uncompressed" or "both".
+//
+// Returns:
+// - AddressMode: The parsed mode.
+// - error: An error if the name is not a known mode.
+func ParseAddressMode(name string) (AddressMode, error) {
+	for mode, modeName := range addressModeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return CompressedMode, fmt.Errorf("unknown address mode %q", name)
+}
+
+// String returns the command-line name of the AddressMode.
+func (mode AddressMode) String() string {
+	return addressModeNames[mode]
+}
+
+// Compressed reports whether the mode hashes compressed public keys.
+func (mode AddressMode) Compressed() bool {
+	return mode == CompressedMode || mode == BothMode
+}
+
+// Uncompressed reports whether the mode hashes uncompressed public keys.
+func (mode AddressMode) Uncompressed() bool {
+	return mode == UncompressedMode || mode == BothMode
+}
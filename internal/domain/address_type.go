@@ -0,0 +1,74 @@
+package domain
+
+// AddressType identifies the script type of a target address listed in wallets.json.
+type AddressType int
+
+const (
+	// P2PKH is a legacy Base58Check pay-to-public-key-hash address ("1...").
+	P2PKH AddressType = iota
+	// P2WPKH is a native SegWit version 0 pay-to-witness-public-key-hash address ("bc1q...").
+	P2WPKH
+	// P2SHP2WPKH is a nested SegWit address ("3...") paying to the script hash of a P2WPKH redeem script.
+	P2SHP2WPKH
+	// P2TR is a Taproot address ("bc1p...") committing to a tweaked x-only public key.
+	P2TR
+	// PubKey is a raw hex-encoded compressed or uncompressed public key, matched without any hashing.
+	PubKey
+	// Invalid is a listed address that could not be decoded. It keeps its position so wallet numbers stay
+	// aligned, but it never matches.
+	Invalid
+)
+
+// addressTypeNames maps every AddressType to its display name.
+var addressTypeNames = map[AddressType]string{
+	P2PKH:      "p2pkh",
+	P2WPKH:     "p2wpkh",
+	P2SHP2WPKH: "p2sh-p2wpkh",
+	P2TR:       "p2tr",
+	PubKey:     "pubkey",
+	Invalid:    "invalid",
+}
+
+// String returns the display name of the address type.
+//
+// Returns:
+// - string: The name of the address type.
+func (t AddressType) String() string {
+	return addressTypeNames[t]
+}
+
+// PubKeyHash reports whether an address of this type pays to the Hash160 of a public key.
+//
+// Returns:
+// - bool: True if the address commits to a public key hash.
+func (t AddressType) PubKeyHash() bool {
+	return t == P2PKH || t == P2WPKH
+}
+
+// ScriptHash reports whether an address of this type pays to the Hash160 of a redeem script rather than to the
+// Hash160 of a public key.
+//
+// Returns:
+// - bool: True if the address commits to a script hash.
+func (t AddressType) ScriptHash() bool {
+	return t == P2SHP2WPKH
+}
+
+// AcceptsUncompressed reports whether an address of this type can be paid to the hash of an uncompressed
+// public key. Witness programs only commit to compressed keys.
+//
+// Returns:
+// - bool: True if the uncompressed public key hash can match an address of this type.
+func (t AddressType) AcceptsUncompressed() bool {
+	return t == P2PKH
+}
+
+// WalletEntry describes a target address as it was listed in wallets.json.
+//
+// Fields:
+// - Address: The address string exactly as listed.
+// - Type: The script type of the address.
+type WalletEntry struct {
+	Address string
+	Type    AddressType
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "math/big"
+
 // Range represents a range with a minimum and maximum value and a status.
 //
 // Fields:
@@ -24,8 +26,17 @@ type Ranges struct {
 //
 // Fields:
 // - Addresses: A slice of byte slices, where each byte slice represents a wallet address.
+// - Entries: The listed form and type of each address, parallel to Addresses (may be nil).
+// - index: A Hash160Index over the 20-byte Addresses, built by NewWallets.
+// - xOnly: A map from the 32-byte x-only Addresses to their positions, built by NewWallets.
+// - pubKeys: A map from the 33-byte compressed public key Addresses to their positions, built by NewWallets.
 type Wallets struct {
-	Addresses [][]byte `json:"wallets"`
+	Addresses [][]byte      `json:"wallets"`
+	Entries   []WalletEntry `json:"-"`
+
+	index   *Hash160Index
+	xOnly   map[[XOnlyKeySize]byte]int
+	pubKeys map[[CompressedPubKeySize]byte]int
 }
 
 // Parameters represents the configuration parameters for the application.
@@ -35,22 +46,72 @@ type Wallets struct {
 // - TargetWallet: Index of the target wallet (integer).
 // - UpdateInterval: Interval for progress updates in seconds (integer).
 // - BatchCount: Number of batches (integer).
+// - GroupSize: Number of consecutive keys that share a single modular inversion (integer).
+// - CheckpointMins: Minutes between checkpoints of the progress of a running batch, or 0 to disable them (integer).
 // - BatchSize: Size of each batch (int64).
+// - WorkUnitSize: Number of consecutive keys handed to a worker at once (int64).
+// - CheckpointKeys: Keys checked between checkpoints of the progress of a running batch, or 0 to disable them (int64).
+// - AddressMode: Public key encodings hashed for every key (AddressMode).
+// - SearchMode: Algorithm used to search the key of the target wallet (SearchMode).
+// - GapMode: Where batches are placed inside the largest uncovered gap, or NoGap for the other schedules (GapMode).
+// - BitmapBlockSize: Number of keys per bit of the bitmap progress store, or 0 to keep the progress as intervals (int64).
+// - MemoryBudget: Memory in bytes the baby-step table of the BSGS mode may use (int64).
+// - Seed: Seed of the pseudorandom start locations, so a random run can be replayed (uint64).
+// - PublicKey: Serialized target public key given on the command line, used by the kangaroo and BSGS modes (byte slice).
 // - Rng: Flag to indicate if a random start location should be generated (boolean).
+// - Permutation: Flag to visit the blocks of the range in a seeded pseudorandom order that never repeats (boolean).
+// - SkipInvalid: Flag to skip invalid wallet addresses with a warning instead of refusing to start (boolean).
 // - VerboseSummary: Flag to enable or disable verbose summary output (boolean).
 // - VerboseProgress: Flag to enable or disable verbose progress output (boolean).
 // - VerboseKeyFind: Flag to enable or disable verbose key find output (boolean).
 //
 // Note: The Parameters struct layout is designed with memory alignment considerations,
-// so the boolean fields are followed by 4 bytes of padding.
+// so the boolean fields are followed by 2 bytes of padding.
 type Parameters struct {
-	WorkerCount     int   // 4 bytes
-	TargetWallet    int   // 4 bytes
-	UpdateInterval  int   // 4 bytes
-	BatchCount      int   // 4 bytes
-	BatchSize       int64 // 8 bytes
-	Rng             bool  // 1 byte
-	VerboseSummary  bool  // 1 byte
-	VerboseProgress bool  // 1 byte
-	VerboseKeyFind  bool  // 1 byte + 4 bytes padding
+	WorkerCount     int         // 4 bytes
+	TargetWallet    int         // 4 bytes
+	UpdateInterval  int         // 4 bytes
+	BatchCount      int         // 4 bytes
+	GroupSize       int         // 4 bytes
+	CheckpointMins  int         // 4 bytes
+	BatchSize       int64       // 8 bytes
+	WorkUnitSize    int64       // 8 bytes
+	CheckpointKeys  int64       // 8 bytes
+	MemoryBudget    int64       // 8 bytes
+	BitmapBlockSize int64       // 8 bytes
+	Seed            uint64      // 8 bytes
+	AddressMode     AddressMode // 4 bytes
+	SearchMode      SearchMode  // 4 bytes
+	GapMode         GapMode     // 4 bytes
+	PublicKey       []byte      // 24 bytes
+	Rng             bool        // 1 byte
+	Permutation     bool        // 1 byte
+	SkipInvalid     bool        // 1 byte
+	VerboseSummary  bool        // 1 byte
+	VerboseProgress bool        // 1 byte
+	VerboseKeyFind  bool        // 1 byte + 2 bytes padding
+}
+
+// MatchInfo describes how a matcher derived the target that a private key hit.
+//
+// Fields:
+// - Matcher: The name of the matcher that reported the hit.
+// - PubKeyFormat: The public key form that matched: "compressed", "uncompressed" or "x-only".
+// - Compressed: Whether the private key must be imported with a compressed public key.
+type MatchInfo struct {
+	Matcher      string
+	PubKeyFormat string
+	Compressed   bool
+}
+
+// Match represents a private key whose public key hit one of the wallet addresses.
+//
+// Fields:
+// - Key: The private key that produced the match.
+// - Wallet: The position of the matched address in Wallets.Addresses.
+// - Info: The metadata of the matcher that reported the hit.
+type Match struct {
+	Key    *big.Int
+	Wallet int
+	Info   MatchInfo
 }
@@ -0,0 +1,44 @@
+package domain
+
+import "fmt"
+
+// GapMode selects where the largest-gap-first schedule places a batch inside the largest gap of the progress.
+type GapMode int
+
+const (
+	// NoGap disables the largest-gap-first schedule.
+	NoGap GapMode = iota
+	// CenterGap places the batch in the middle of the gap, so the gaps left on both sides are as even as possible.
+	CenterGap
+	// RandomGap places the batch at a seeded pseudorandom offset inside the gap.
+	RandomGap
+)
+
+// gapModeNames maps every GapMode to its command-line name.
+var gapModeNames = map[GapMode]string{
+	NoGap:     "",
+	CenterGap: "center",
+	RandomGap: "random",
+}
+
+// ParseGapMode converts a command-line name into a GapMode.
+//
+// Parameters:
+// - name: The name of the placement: "center", "random", or empty to disable the schedule.
+//
+// Returns:
+// - GapMode: The parsed placement.
+// - error: An error if the name is not a known placement.
+func ParseGapMode(name string) (GapMode, error) {
+	for mode, modeName := range gapModeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return NoGap, fmt.Errorf("unknown gap mode %q", name)
+}
+
+// String returns the command-line name of the GapMode.
+func (mode GapMode) String() string {
+	return gapModeNames[mode]
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "fmt"
+
+// SearchMode selects the algorithm used to search the private key of the target wallet.
+type SearchMode int
+
+const (
+	// ScanMode derives every key of the range and checks it against the wallet addresses.
+	ScanMode SearchMode = iota
+	// KangarooMode runs Pollard's kangaroo method against a known public key, in about 2*sqrt(range) operations.
+	KangarooMode
+	// BSGSMode runs the baby-step giant-step method against a known public key, with a baby-step table bounded by
+	// a memory budget.
+	BSGSMode
+)
+
+// searchModeNames maps every SearchMode to its command-line name.
+var searchModeNames = map[SearchMode]string{
+	ScanMode:     "scan",
+	KangarooMode: "kangaroo",
+	BSGSMode:     "bsgs",
+}
+
+// ParseSearchMode converts a command-line name into a SearchMode.
+//
+// Parameters:
+// - name: The name of the mode: "scan", "kangaroo" or "bsgs".
+//
+// Returns:
+// - SearchMode: The parsed mode.
+// - error: An error if the name is not a known mode.
+func ParseSearchMode(name string) (SearchMode, error) {
+	for mode, modeName := range searchModeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return ScanMode, fmt.Errorf("unknown search mode %q", name)
+}
+
+// String returns the command-line name of the SearchMode.
+func (mode SearchMode) String() string {
+	return searchModeNames[mode]
+}
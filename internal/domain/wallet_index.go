@@ -0,0 +1,266 @@
+package domain
+
+import "bytes"
+
+// Hash160Size is the size in bytes of a Hash160 (RIPEMD-160 of SHA-256) digest.
+const Hash160Size = 20
+
+// XOnlyKeySize is the size in bytes of an x-only public key, as committed to by Taproot outputs.
+const XOnlyKeySize = 32
+
+// CompressedPubKeySize is the size in bytes of a compressed public key, the form raw public key targets are stored in.
+const CompressedPubKeySize = 33
+
+// prefixFilterBits is the number of leading hash bits used by the pre-filter of a Hash160Index.
+const prefixFilterBits = 16
+
+// Hash160Index is a constant-time lookup structure for Hash160 targets.
+//
+// A bitset keyed by the first prefixFilterBits bits of each hash sits in front of the exact map, so a hash that
+// is not a target is almost always rejected with a single memory access.
+type Hash160Index struct {
+	filter  [(1 << prefixFilterBits) / 64]uint64
+	entries map[[Hash160Size]byte]int
+}
+
+// NewHash160Index builds an index over the given hashes, mapping each one to its position in the slice.
+// Entries that are not Hash160Size bytes long are skipped; when a hash appears twice, the first position is kept.
+//
+// Parameters:
+// - hashes: A slice of byte slices, each one holding a Hash160.
+//
+// Returns:
+// - *Hash160Index: A pointer to the newly created index.
+func NewHash160Index(hashes [][]byte) *Hash160Index {
+	index := &Hash160Index{entries: make(map[[Hash160Size]byte]int, len(hashes))}
+	for i, hash := range hashes {
+		if len(hash) != Hash160Size {
+			continue
+		}
+		var key [Hash160Size]byte
+		copy(key[:], hash)
+		if _, exists := index.entries[key]; !exists {
+			index.entries[key] = i
+			prefix := prefixOf(&key)
+			index.filter[prefix/64] |= 1 << (prefix % 64)
+		}
+	}
+	return index
+}
+
+// Find returns the position of a hash in the index, or -1 if the hash is not indexed.
+//
+// Parameters:
+// - hash: A pointer to the Hash160 to look up.
+//
+// Returns:
+// - int: The position of the hash, or -1 if it is not found.
+func (index *Hash160Index) Find(hash *[Hash160Size]byte) int {
+	prefix := prefixOf(hash)
+	if index.filter[prefix/64]&(1<<(prefix%64)) == 0 {
+		return -1
+	}
+	if i, exists := index.entries[*hash]; exists {
+		return i
+	}
+	return -1
+}
+
+// prefixOf returns the leading prefixFilterBits bits of a hash.
+func prefixOf(hash *[Hash160Size]byte) uint32 {
+	return uint32(hash[0])<<8 | uint32(hash[1])
+}
+
+// NewWallets creates a Wallets instance from decoded addresses and builds its lookup indexes.
+// Addresses of Hash160Size bytes are indexed as hashes, addresses of XOnlyKeySize bytes as x-only keys and
+// addresses of CompressedPubKeySize bytes as raw public keys.
+//
+// Parameters:
+// - addresses: A slice of byte slices, where each byte slice represents a wallet address Hash160 or x-only key.
+//
+// Returns:
+// - *Wallets: A pointer to the newly created Wallets instance.
+func NewWallets(addresses [][]byte) *Wallets {
+	xOnly := make(map[[XOnlyKeySize]byte]int)
+	pubKeys := make(map[[CompressedPubKeySize]byte]int)
+	for i, address := range addresses {
+		switch len(address) {
+		case XOnlyKeySize:
+			var key [XOnlyKeySize]byte
+			copy(key[:], address)
+			if _, exists := xOnly[key]; !exists {
+				xOnly[key] = i
+			}
+		case CompressedPubKeySize:
+			var key [CompressedPubKeySize]byte
+			copy(key[:], address)
+			if _, exists := pubKeys[key]; !exists {
+				pubKeys[key] = i
+			}
+		}
+	}
+	return &Wallets{Addresses: addresses, index: NewHash160Index(addresses), xOnly: xOnly, pubKeys: pubKeys}
+}
+
+// Find returns the position of a Hash160 in the wallet addresses, or -1 if it is not a target.
+//
+// Wallets created through NewWallets answer in constant time through their index; otherwise the addresses are
+// scanned linearly.
+//
+// Parameters:
+// - hash: The Hash160 to look up.
+//
+// Returns:
+// - int: The position of the address, or -1 if it is not found.
+func (w *Wallets) Find(hash []byte) int {
+	if len(hash) != Hash160Size {
+		return -1
+	}
+	var key [Hash160Size]byte
+	copy(key[:], hash)
+	return w.FindHash160(&key)
+}
+
+// FindHash160 returns the position of a fixed-size Hash160 in the wallet addresses, or -1 if it is not a target.
+//
+// Parameters:
+// - hash: A pointer to the Hash160 to look up.
+//
+// Returns:
+// - int: The position of the address, or -1 if it is not found.
+func (w *Wallets) FindHash160(hash *[Hash160Size]byte) int {
+	if w.index == nil {
+		for i, address := range w.Addresses {
+			if bytes.Equal(address, hash[:]) {
+				return i
+			}
+		}
+		return -1
+	}
+	return w.index.Find(hash)
+}
+
+// FindXOnly returns the position of an x-only public key in the wallet addresses, or -1 if it is not a target.
+//
+// Parameters:
+// - key: A pointer to the x-only public key to look up.
+//
+// Returns:
+// - int: The position of the address, or -1 if it is not found.
+func (w *Wallets) FindXOnly(key *[XOnlyKeySize]byte) int {
+	if w.xOnly == nil {
+		for i, address := range w.Addresses {
+			if bytes.Equal(address, key[:]) {
+				return i
+			}
+		}
+		return -1
+	}
+	if i, exists := w.xOnly[*key]; exists {
+		return i
+	}
+	return -1
+}
+
+// FindPubKey returns the position of the public key with the given x coordinate and y parity in the wallet addresses,
+// or -1 if it is not a target. Raw public key targets are stored in compressed form, so the lookup key is the parity
+// prefix followed by the serialized x coordinate.
+//
+// Parameters:
+// - x: A pointer to the big-endian x coordinate of the public key.
+// - oddY: Whether the y coordinate of the public key is odd.
+//
+// Returns:
+// - int: The position of the address, or -1 if it is not found.
+func (w *Wallets) FindPubKey(x *[XOnlyKeySize]byte, oddY bool) int {
+	prefix := byte(0x02)
+	if oddY {
+		prefix = 0x03
+	}
+	if w.pubKeys == nil {
+		for i, address := range w.Addresses {
+			if len(address) == CompressedPubKeySize && address[0] == prefix && bytes.Equal(address[1:], x[:]) {
+				return i
+			}
+		}
+		return -1
+	}
+	var key [CompressedPubKeySize]byte
+	key[0] = prefix
+	copy(key[1:], x[:])
+	if i, exists := w.pubKeys[key]; exists {
+		return i
+	}
+	return -1
+}
+
+// Entry returns the listed form of the address at the given position. Wallets without entries describe every
+// address as P2PKH with an empty listed form.
+//
+// Parameters:
+// - i: The position of the address.
+//
+// Returns:
+// - WalletEntry: The listed form and type of the address.
+func (w *Wallets) Entry(i int) WalletEntry {
+	if i < 0 || i >= len(w.Entries) {
+		return WalletEntry{Type: P2PKH}
+	}
+	return w.Entries[i]
+}
+
+// Contains reports whether a Hash160 is one of the wallet addresses.
+//
+// Parameters:
+// - hash: A pointer to the Hash160 to look up.
+//
+// Returns:
+// - bool: True if the hash is a target, false otherwise.
+func (w *Wallets) Contains(hash *[Hash160Size]byte) bool {
+	return w.FindHash160(hash) != -1
+}
+
+// HasType reports whether any wallet address is of the given type.
+//
+// Parameters:
+// - addressType: The address type to look for.
+//
+// Returns:
+// - bool: True if at least one address has the given type.
+func (w *Wallets) HasType(addressType AddressType) bool {
+	return w.hasType(func(t AddressType) bool { return t == addressType })
+}
+
+// HasPubKeyHashes reports whether any wallet address commits to the Hash160 of a public key.
+//
+// Returns:
+// - bool: True if at least one address commits to a public key hash.
+func (w *Wallets) HasPubKeyHashes() bool {
+	return w.hasType(AddressType.PubKeyHash)
+}
+
+// HasScriptHashes reports whether any wallet address commits to a script hash.
+//
+// Returns:
+// - bool: True if at least one address commits to a script hash.
+func (w *Wallets) HasScriptHashes() bool {
+	return w.hasType(AddressType.ScriptHash)
+}
+
+// HasUncompressedTargets reports whether any wallet address can be paid to the Hash160 of an uncompressed public key.
+//
+// Returns:
+// - bool: True if at least one address accepts uncompressed public keys.
+func (w *Wallets) HasUncompressedTargets() bool {
+	return w.hasType(AddressType.AcceptsUncompressed)
+}
+
+// hasType reports whether the type of any wallet address satisfies the given predicate.
+func (w *Wallets) hasType(predicate func(AddressType) bool) bool {
+	for i := range w.Addresses {
+		if predicate(w.Entry(i).Type) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+)
+
+// testHashes returns count distinct 20-byte hashes.
+func testHashes(count int) [][]byte {
+	hashes := make([][]byte, count)
+	for i := range hashes {
+		sum := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+		hashes[i] = sum[:Hash160Size]
+	}
+	return hashes
+}
+
+func TestWalletsFind_IndexedPositions(t *testing.T) {
+	hashes := testHashes(160)
+	wallets := NewWallets(hashes)
+	for i, hash := range hashes {
+		if result := wallets.Find(hash); result != i {
+			t.Errorf("expected %d, got %d", i, result)
+		}
+	}
+}
+
+func TestWalletsFind_Missing(t *testing.T) {
+	hashes := testHashes(161)
+	wallets := NewWallets(hashes[:160])
+	if result := wallets.Find(hashes[160]); result != -1 {
+		t.Errorf("expected -1, got %d", result)
+	}
+	if wallets.Find([]byte{1, 2, 3}) != -1 {
+		t.Errorf("expected -1 for a short hash")
+	}
+}
+
+func TestWalletsFind_DuplicateKeepsFirst(t *testing.T) {
+	hashes := testHashes(3)
+	hashes = append(hashes, bytes.Clone(hashes[1]))
+	wallets := NewWallets(hashes)
+	if result := wallets.Find(hashes[3]); result != 1 {
+		t.Errorf("expected 1, got %d", result)
+	}
+}
+
+func TestWalletsFind_WithoutIndex(t *testing.T) {
+	hashes := testHashes(10)
+	wallets := Wallets{Addresses: hashes}
+	if result := wallets.Find(hashes[4]); result != 4 {
+		t.Errorf("expected 4, got %d", result)
+	}
+}
+
+func TestWalletsFindPubKey_BothParities(t *testing.T) {
+	var x [XOnlyKeySize]byte
+	copy(x[:], testHashes(1)[0])
+	even := append([]byte{0x02}, x[:]...)
+	odd := append([]byte{0x03}, x[:]...)
+	indexed := NewWallets([][]byte{even, odd})
+	for _, wallets := range []*Wallets{indexed, {Addresses: indexed.Addresses}} {
+		if result := wallets.FindPubKey(&x, false); result != 0 {
+			t.Errorf("expected 0, got %d", result)
+		}
+		if result := wallets.FindPubKey(&x, true); result != 1 {
+			t.Errorf("expected 1, got %d", result)
+		}
+	}
+}
+
+func BenchmarkWalletsContains(b *testing.B) {
+	hashes := testHashes(161)
+	wallets := NewWallets(hashes[:160])
+	var miss [Hash160Size]byte
+	copy(miss[:], hashes[160])
+	for i := 0; i < b.N; i++ {
+		wallets.Contains(&miss)
+	}
+}
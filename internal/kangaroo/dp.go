@@ -0,0 +1,312 @@
+package kangaroo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// DPFileVersion is the version of the distinguished point file format written by this package.
+const DPFileVersion = 1
+
+// dpKeySize is the number of low x coordinate bytes a distinguished point is stored and compared by.
+// A false match only costs a failed key check or a needless restart.
+const dpKeySize = 16
+
+// dpMagic starts every distinguished point file.
+var dpMagic = [4]byte{'G', 'K', 'D', 'P'}
+
+// dpHeaderSize is the size of the file header: magic, version, min, max, target, mean, jump table id and bits.
+const dpHeaderSize = 4 + 1 + 32 + 32 + secp256k1.PubKeyBytesLenCompressed + 32 + 32 + 1
+
+// dpTameFlag marks a tame kangaroo in the flags byte of a record; the low bits hold the length of the distance.
+const dpTameFlag = 0x80
+
+// DPHeader identifies the search a distinguished point file belongs to: the puzzle, given by its range and
+// target, and the jump table, given by its mean and the SHA-256 of its distances. Files can only be resumed
+// or merged together when both match; the distinguished point bits may differ.
+//
+// Fields:
+// - Min: The smallest private key of the range.
+// - Max: The largest private key of the range.
+// - Target: The compressed target public key.
+// - Mean: The mean jump distance the jump table was derived from.
+// - JumpTable: The SHA-256 of the jump distances.
+// - DPBits: The number of distinguished point bits of the run that created the file.
+type DPHeader struct {
+	Min       *big.Int
+	Max       *big.Int
+	Target    [secp256k1.PubKeyBytesLenCompressed]byte
+	Mean      *big.Int
+	JumpTable [32]byte
+	DPBits    int
+}
+
+// SamePuzzle reports whether two headers describe the same range and target.
+//
+// Parameters:
+// - other: The header to compare with.
+//
+// Returns:
+// - bool: True if both headers belong to the same puzzle.
+func (h DPHeader) SamePuzzle(other DPHeader) bool {
+	return h.Min.Cmp(other.Min) == 0 && h.Max.Cmp(other.Max) == 0 && h.Target == other.Target
+}
+
+// Compatible checks that points of a file with the other header can be combined with points of this header.
+//
+// Parameters:
+// - other: The header to compare with.
+//
+// Returns:
+// - error: An error describing the mismatch, or nil if the files are compatible.
+func (h DPHeader) Compatible(other DPHeader) error {
+	if !h.SamePuzzle(other) {
+		return errors.New("written for a different puzzle")
+	}
+	if h.Mean.Cmp(other.Mean) != 0 || h.JumpTable != other.JumpTable {
+		return errors.New("written with a different jump table")
+	}
+	return nil
+}
+
+// marshal encodes the header as it is written at the start of a file.
+func (h DPHeader) marshal() []byte {
+	buf := make([]byte, 0, dpHeaderSize)
+	buf = append(buf, dpMagic[:]...)
+	buf = append(buf, DPFileVersion)
+	buf = append(buf, h.Min.FillBytes(make([]byte, 32))...)
+	buf = append(buf, h.Max.FillBytes(make([]byte, 32))...)
+	buf = append(buf, h.Target[:]...)
+	buf = append(buf, h.Mean.FillBytes(make([]byte, 32))...)
+	buf = append(buf, h.JumpTable[:]...)
+	return append(buf, byte(h.DPBits))
+}
+
+// unmarshalDPHeader decodes a header written by marshal.
+func unmarshalDPHeader(buf []byte) (DPHeader, error) {
+	if !bytes.Equal(buf[:4], dpMagic[:]) {
+		return DPHeader{}, errors.New("not a distinguished point file")
+	}
+	if buf[4] != DPFileVersion {
+		return DPHeader{}, fmt.Errorf("unsupported distinguished point file version %d", buf[4])
+	}
+	var h DPHeader
+	buf = buf[5:]
+	h.Min, buf = new(big.Int).SetBytes(buf[:32]), buf[32:]
+	h.Max, buf = new(big.Int).SetBytes(buf[:32]), buf[32:]
+	buf = buf[copy(h.Target[:], buf):]
+	h.Mean, buf = new(big.Int).SetBytes(buf[:32]), buf[32:]
+	buf = buf[copy(h.JumpTable[:], buf):]
+	h.DPBits = int(buf[0])
+	return h, nil
+}
+
+// ReadDPHeader reads the header of a distinguished point file.
+//
+// Parameters:
+// - path: The path of the file.
+//
+// Returns:
+// - DPHeader: The header of the file.
+// - error: An error if the file cannot be read or is not a distinguished point file.
+func ReadDPHeader(path string) (DPHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return DPHeader{}, err
+	}
+	defer file.Close()
+	buf := make([]byte, dpHeaderSize)
+	if _, err := io.ReadFull(file, buf); err != nil {
+		return DPHeader{}, fmt.Errorf("%s: truncated header", path)
+	}
+	header, err := unmarshalDPHeader(buf)
+	if err != nil {
+		return DPHeader{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return header, nil
+}
+
+// readDPFile reads a distinguished point file, calling visit with every record. A record cut short by a crash
+// ends the file: its offset is returned so the writer can drop it before appending.
+//
+// Returns:
+// - DPHeader: The header of the file.
+// - int64: The size of the file up to the last complete record.
+// - error: An error if the file cannot be read or is malformed.
+func readDPFile(path string, visit func(key *[dpKeySize]byte, entry dpEntry)) (DPHeader, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return DPHeader{}, 0, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	buf := make([]byte, dpHeaderSize)
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		return DPHeader{}, 0, fmt.Errorf("%s: truncated header", path)
+	}
+	header, err := unmarshalDPHeader(buf)
+	if err != nil {
+		return DPHeader{}, 0, fmt.Errorf("%s: %v", path, err)
+	}
+
+	offset := int64(dpHeaderSize)
+	var record [dpKeySize + 1 + 32]byte
+	for {
+		if _, err := io.ReadFull(reader, record[:dpKeySize+1]); err != nil {
+			return header, offset, nil
+		}
+		length := int(record[dpKeySize] &^ dpTameFlag)
+		if length > 32 {
+			return DPHeader{}, 0, fmt.Errorf("%s: malformed record at offset %d", path, offset)
+		}
+		if _, err := io.ReadFull(reader, record[dpKeySize+1:dpKeySize+1+length]); err != nil {
+			return header, offset, nil
+		}
+		var key [dpKeySize]byte
+		var entry dpEntry
+		copy(key[:], record[:dpKeySize])
+		entry.dist.SetByteSlice(record[dpKeySize+1 : dpKeySize+1+length])
+		entry.tame = record[dpKeySize]&dpTameFlag != 0
+		visit(&key, entry)
+		offset += int64(dpKeySize + 1 + length)
+	}
+}
+
+// dpEntry is a distinguished point reached by a kangaroo: its distance from the start of its herd's frame and
+// whether the kangaroo was tame.
+type dpEntry struct {
+	dist secp256k1.ModNScalar
+	tame bool
+}
+
+// dpTable stores the distinguished points reached by every kangaroo, keyed by the low bytes of their x coordinate.
+//
+// When a file is attached, every new point is appended to it as a compact binary record right away, so a long
+// run can be resumed and its points shared with other nodes.
+type dpTable struct {
+	mu      sync.Mutex
+	entries map[[dpKeySize]byte]dpEntry
+	file    *os.File
+}
+
+// newDPTable creates an empty distinguished point table kept in memory.
+func newDPTable() *dpTable {
+	return &dpTable{entries: make(map[[dpKeySize]byte]dpEntry)}
+}
+
+// openDPTable creates a distinguished point table backed by the file at path. An existing file is loaded and
+// appended to, and must be compatible with header; a missing file is created with header.
+//
+// Parameters:
+// - path: The path of the distinguished point file.
+// - header: The header identifying the puzzle and the jump table.
+//
+// Returns:
+// - *dpTable: A pointer to the newly created table.
+// - error: An error if the file cannot be read or written, or if it belongs to a different search.
+func openDPTable(path string, header DPHeader) (*dpTable, error) {
+	table := newDPTable()
+	stored, size, err := readDPFile(path, func(key *[dpKeySize]byte, entry dpEntry) {
+		if _, exists := table.entries[*key]; !exists {
+			table.entries[*key] = entry
+		}
+	})
+	switch {
+	case os.IsNotExist(err):
+		size = 0
+	case err != nil:
+		return nil, err
+	default:
+		if err := header.Compatible(stored); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		_, err = file.Write(header.marshal())
+	} else if err = file.Truncate(size); err == nil {
+		_, err = file.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	table.file = file
+	return table, nil
+}
+
+// add stores a distinguished point, unless one with the same key is already known.
+//
+// Parameters:
+// - x: A pointer to the big-endian x coordinate of the point.
+// - entry: The distance and herd of the kangaroo that reached it.
+//
+// Returns:
+// - dpEntry: The entry already stored for the same key.
+// - bool: True if the point was already known, in which case nothing is stored.
+// - error: An error if the point could not be appended to the file.
+func (t *dpTable) add(x *[32]byte, entry dpEntry) (dpEntry, bool, error) {
+	var key [dpKeySize]byte
+	copy(key[:], x[32-dpKeySize:])
+	return t.addKey(&key, entry)
+}
+
+// addKey stores a distinguished point by its key, unless the key is already known, and returns the same values
+// as add.
+func (t *dpTable) addKey(key *[dpKeySize]byte, entry dpEntry) (dpEntry, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if other, exists := t.entries[*key]; exists {
+		return other, true, nil
+	}
+	t.entries[*key] = entry
+	if t.file != nil {
+		if _, err := t.file.Write(marshalDPRecord(key, entry)); err != nil {
+			return dpEntry{}, false, err
+		}
+	}
+	return dpEntry{}, false, nil
+}
+
+// marshalDPRecord encodes a record: the key, a flags byte holding the herd and the length of the distance, and
+// the distance in big-endian without leading zeros.
+func marshalDPRecord(key *[dpKeySize]byte, entry dpEntry) []byte {
+	dist := entry.dist.Bytes()
+	trimmed := bytes.TrimLeft(dist[:], "\x00")
+	flags := byte(len(trimmed))
+	if entry.tame {
+		flags |= dpTameFlag
+	}
+	record := make([]byte, 0, dpKeySize+1+len(trimmed))
+	record = append(record, key[:]...)
+	record = append(record, flags)
+	return append(record, trimmed...)
+}
+
+// size returns the number of distinguished points in the table.
+func (t *dpTable) size() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
+
+// close closes the distinguished point file, if any.
+func (t *dpTable) close() error {
+	if t.file == nil {
+		return nil
+	}
+	return t.file.Close()
+}
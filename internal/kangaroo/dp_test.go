@@ -0,0 +1,181 @@
+package kangaroo
+
+import (
+	"GoKeyHunt/internal/utils"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// testHeader returns the header of a search for key in the 28-bit puzzle range.
+func testHeader(t *testing.T, key *big.Int) DPHeader {
+	t.Helper()
+	minimum, maximum := puzzleRange(28)
+	solver, err := NewSolver(Config{
+		Min: minimum, Max: maximum, Target: utils.CreatePublicPoint(key), Workers: 1, HerdSize: 2, DPBits: -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return solver.Header()
+}
+
+// pointX returns the big-endian x coordinate of d*G, or of d*G + offset*G for a wild kangaroo.
+func pointX(distance int64) *[32]byte {
+	var scalar secp256k1.ModNScalar
+	var point secp256k1.JacobianPoint
+	scalar.SetByteSlice(big.NewInt(distance).Bytes())
+	secp256k1.ScalarBaseMultNonConst(&scalar, &point)
+	point.ToAffine()
+	var x [32]byte
+	point.X.PutBytesUnchecked(x[:])
+	return &x
+}
+
+// writeDPFile creates a distinguished point file holding points reached at the given distances. Tame points lie at
+// d*G, wild points at (key - min + d)*G.
+func writeDPFile(t *testing.T, path string, header DPHeader, key *big.Int, tame bool, distances ...int64) {
+	t.Helper()
+	table, err := openDPTable(path, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.close()
+	offset := int64(0)
+	if !tame {
+		offset = new(big.Int).Sub(key, header.Min).Int64()
+	}
+	for _, distance := range distances {
+		var entry dpEntry
+		entry.dist.SetByteSlice(big.NewInt(distance).Bytes())
+		entry.tame = tame
+		table.add(pointX(offset+distance), entry)
+	}
+}
+
+func TestMerge_FindsTameWildCollision(t *testing.T) {
+	dir := t.TempDir()
+	key := big.NewInt(0xd916ce8)
+	header := testHeader(t, key)
+	offset := new(big.Int).Sub(key, header.Min).Int64()
+
+	tamePath, wildPath := filepath.Join(dir, "tame.dp"), filepath.Join(dir, "wild.dp")
+	writeDPFile(t, tamePath, header, key, true, 11, 12, offset+3)
+	writeDPFile(t, wildPath, header, key, false, 3, 4)
+
+	output := filepath.Join(dir, "merged.dp")
+	result, err := Merge(output, []string{tamePath, wildPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Key == nil || result.Key.Cmp(key) != 0 {
+		t.Errorf("expected %x, got %x", key, result.Key)
+	}
+	if result.Points != 4 || result.Duplicates != 1 {
+		t.Errorf("expected 4 points and 1 duplicate, got %d and %d", result.Points, result.Duplicates)
+	}
+	if records := countRecords(t, output); records != 4 {
+		t.Errorf("expected %d records, got %d", 4, records)
+	}
+
+	// The output is never overwritten.
+	if _, err := Merge(output, []string{tamePath}); err == nil {
+		t.Errorf("expected an error for an existing output file")
+	}
+}
+
+func TestMerge_WithoutCollision(t *testing.T) {
+	dir := t.TempDir()
+	key := big.NewInt(0xd916ce8)
+	header := testHeader(t, key)
+	first, second := filepath.Join(dir, "first.dp"), filepath.Join(dir, "second.dp")
+	writeDPFile(t, first, header, key, true, 1, 2, 3)
+	writeDPFile(t, second, header, key, true, 3, 4)
+
+	result, err := Merge("", []string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Key != nil || result.Points != 4 || result.Duplicates != 1 {
+		t.Errorf("expected no key, 4 points and 1 duplicate, got %v, %d and %d", result.Key, result.Points, result.Duplicates)
+	}
+}
+
+func TestMerge_RejectsMismatchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	key := big.NewInt(0xd916ce8)
+	header := testHeader(t, key)
+	base := filepath.Join(dir, "base.dp")
+	writeDPFile(t, base, header, key, true, 1)
+
+	otherTarget := filepath.Join(dir, "target.dp")
+	writeDPFile(t, otherTarget, testHeader(t, big.NewInt(0x8000123)), key, true, 1)
+
+	otherJumps := header
+	otherJumps.Mean = new(big.Int).Add(header.Mean, big.NewInt(1))
+	otherJumps.JumpTable = newJumpTable(otherJumps.Mean).id()
+	otherTable := filepath.Join(dir, "jumps.dp")
+	writeDPFile(t, otherTable, otherJumps, key, true, 1)
+
+	for _, input := range []string{otherTarget, otherTable} {
+		if _, err := Merge("", []string{base, input}); err == nil {
+			t.Errorf("expected an error for %s", input)
+		}
+	}
+
+	notDP := filepath.Join(dir, "wallets.json")
+	if err := os.WriteFile(notDP, []byte(`{"wallets": []}`+"\n"+string(make([]byte, dpHeaderSize))), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Merge("", []string{base, notDP}); err == nil {
+		t.Errorf("expected an error for a file that is not a distinguished point file")
+	}
+}
+
+func TestDPTable_DropsTruncatedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kangaroo.dp")
+	key := big.NewInt(0xd916ce8)
+	header := testHeader(t, key)
+	writeDPFile(t, path, header, key, true, 1, 2)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash in the middle of a record leaves a key and flags without the distance.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write(append(make([]byte, dpKeySize), 8))
+	file.Close()
+
+	table, err := openDPTable(path, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.close()
+	if table.size() != 2 {
+		t.Errorf("expected %d points, got %d", 2, table.size())
+	}
+	if resized, err := os.Stat(path); err != nil || resized.Size() != info.Size() {
+		t.Errorf("expected a file of %d bytes, got %d", info.Size(), resized.Size())
+	}
+}
+
+func TestDPTable_ReportsWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kangaroo.dp")
+	key := big.NewInt(0xd916ce8)
+	table, err := openDPTable(path, testHeader(t, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.close()
+	var x [32]byte
+	if _, _, err := table.add(&x, dpEntry{tame: true}); err == nil {
+		t.Errorf("expected an error for a point that cannot be written")
+	}
+}
@@ -0,0 +1,134 @@
+package kangaroo
+
+import (
+	"GoKeyHunt/internal/core"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// herd is the set of kangaroos owned by a single worker. The first half is tame and the second half is wild.
+// Every kangaroo is kept in affine coordinates, and the inversions of one step of the whole herd are batched.
+type herd struct {
+	solver *Solver
+
+	x, y  []secp256k1.FieldVal
+	dist  []secp256k1.ModNScalar
+	jump  []int
+	diffs []secp256k1.FieldVal
+
+	scratch []secp256k1.FieldVal
+	xBytes  [32]byte
+}
+
+// newHerd creates a herd of solver.config.HerdSize kangaroos at random starting points.
+func newHerd(solver *Solver) *herd {
+	size := solver.config.HerdSize
+	h := &herd{
+		solver:  solver,
+		x:       make([]secp256k1.FieldVal, size),
+		y:       make([]secp256k1.FieldVal, size),
+		dist:    make([]secp256k1.ModNScalar, size),
+		jump:    make([]int, size),
+		diffs:   make([]secp256k1.FieldVal, size),
+		scratch: make([]secp256k1.FieldVal, size),
+	}
+	for i := range h.x {
+		h.spawn(i)
+	}
+	return h
+}
+
+// tame reports whether the kangaroo at index i is tame.
+func (h *herd) tame(i int) bool {
+	return i < len(h.x)/2
+}
+
+// spawn places the kangaroo at index i on a new random starting point. Tame kangaroos start at d*G with d in
+// [1, width]; wild kangaroos start at (target - min*G) + d*G with d in [1, width/2].
+func (h *herd) spawn(i int) {
+	bound := h.solver.width
+	if !h.tame(i) {
+		bound = new(big.Int).Rsh(bound, 1)
+	}
+	h.dist[i].SetByteSlice(randomDistance(bound).Bytes())
+
+	var point secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&h.dist[i], &point)
+	if !h.tame(i) {
+		secp256k1.AddNonConst(&point, &h.solver.shifted, &point)
+	}
+	point.ToAffine()
+	h.x[i].Set(&point.X)
+	h.y[i].Set(&point.Y)
+}
+
+// step makes every kangaroo of the herd jump once and records the distinguished points it reaches.
+func (h *herd) step() {
+	for i := range h.x {
+		h.prepare(i)
+	}
+	core.BatchInverse(h.diffs, h.scratch)
+
+	jumps := h.solver.jumps
+	var lambda, negX, negY, negJumpX, x3 secp256k1.FieldVal
+	for i := range h.x {
+		j := h.jump[i]
+		negX.NegateVal(&h.x[i], 1)
+		negY.NegateVal(&h.y[i], 1)
+		negJumpX.NegateVal(&jumps.x[j], 1)
+
+		// lambda = (yJ - y) / (xJ - x), x3 = lambda^2 - x - xJ, y3 = lambda*(x - x3) - y
+		lambda.Add2(&jumps.y[j], &negY).Mul(&h.diffs[i])
+		x3.SquareVal(&lambda).Add(&negX).Add(&negJumpX).Normalize()
+		h.y[i].NegateVal(&x3, 1).Add(&h.x[i]).Mul(&lambda).Add(&negY).Normalize()
+		h.x[i].Set(&x3)
+		h.dist[i].Add(&jumps.dist[j])
+
+		h.x[i].PutBytesUnchecked(h.xBytes[:])
+		if binary.BigEndian.Uint64(h.xBytes[24:])&h.solver.dpMask == 0 {
+			h.distinguished(i)
+		}
+	}
+	h.solver.jumpCount.Add(uint64(len(h.x)))
+}
+
+// prepare selects the jump of the kangaroo at index i and stores x(jump) - x in h.diffs[i] for the batched
+// inversion. A kangaroo sitting on the jump point or on its negation cannot use the affine addition, so it is
+// restarted.
+func (h *herd) prepare(i int) {
+	jumps := h.solver.jumps
+	for {
+		h.x[i].PutBytesUnchecked(h.xBytes[:])
+		h.jump[i] = jumps.index(&h.xBytes)
+		h.diffs[i].NegateVal(&h.x[i], 1).Add(&jumps.x[h.jump[i]]).Normalize()
+		if !h.diffs[i].IsZero() {
+			return
+		}
+		h.spawn(i)
+	}
+}
+
+// distinguished records the distinguished point of the kangaroo at index i, whose x coordinate is in h.xBytes.
+// A point already reached by the other herd solves the search; a point already reached by the same herd means
+// the two kangaroos now share a path. In both cases the kangaroo is restarted. Only points reached by a jump are
+// recorded, so a restarted kangaroo is never checked again before its next jump. A point that cannot be saved
+// stops the search.
+func (h *herd) distinguished(i int) {
+	tame := h.tame(i)
+	other, exists, err := h.solver.table.add(&h.xBytes, dpEntry{dist: h.dist[i], tame: tame})
+	if err != nil {
+		h.solver.failed.CompareAndSwap(nil, &err)
+		return
+	}
+	if !exists {
+		return
+	}
+	if other.tame && !tame {
+		h.solver.collide(&other.dist, &h.dist[i])
+	} else if !other.tame && tame {
+		h.solver.collide(&h.dist[i], &other.dist)
+	}
+	h.spawn(i)
+}
@@ -0,0 +1,69 @@
+package kangaroo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// jumpCount is the number of distinct jumps of a jump table. It must be a power of two.
+const jumpCount = 32
+
+// jumpTable holds the jumps every kangaroo chooses from. The jump taken from a point only depends on its
+// x coordinate, so two kangaroos that land on the same point follow the same path from then on.
+type jumpTable struct {
+	mean *big.Int
+	x, y [jumpCount]secp256k1.FieldVal
+	dist [jumpCount]secp256k1.ModNScalar
+}
+
+// newJumpTable derives a deterministic jump table whose distances are spread over [1, 2*mean].
+//
+// The distances are drawn from SHA-256 in counter mode, so the same mean always yields the same table and
+// distinguished points stay compatible between runs.
+//
+// Parameters:
+// - mean: The mean jump distance (must be greater than 0).
+//
+// Returns:
+// - *jumpTable: A pointer to the newly created jump table.
+func newJumpTable(mean *big.Int) *jumpTable {
+	table := &jumpTable{mean: new(big.Int).Set(mean)}
+	bound := new(big.Int).Lsh(mean, 1)
+	var seed [12]byte
+	copy(seed[:], "kangaroo")
+	for i := 0; i < jumpCount; i++ {
+		binary.BigEndian.PutUint32(seed[8:], uint32(i))
+		digest := sha256.Sum256(seed[:])
+		distance := new(big.Int).SetBytes(digest[:])
+		distance.Mod(distance, bound).Add(distance, big.NewInt(1))
+
+		var point secp256k1.JacobianPoint
+		table.dist[i].SetByteSlice(distance.Bytes())
+		secp256k1.ScalarBaseMultNonConst(&table.dist[i], &point)
+		point.ToAffine()
+		table.x[i].Set(&point.X)
+		table.y[i].Set(&point.Y)
+	}
+	return table
+}
+
+// id returns the SHA-256 of the jump distances, which identifies the table in distinguished point files.
+func (t *jumpTable) id() [32]byte {
+	digest := sha256.New()
+	for i := range t.dist {
+		dist := t.dist[i].Bytes()
+		digest.Write(dist[:])
+	}
+	var id [32]byte
+	digest.Sum(id[:0])
+	return id
+}
+
+// index returns the jump taken from a point with the given big-endian x coordinate.
+// It uses bits above the distinguished point bits, so distinguished points do not all take the same jump.
+func (t *jumpTable) index(x *[32]byte) int {
+	return int(binary.BigEndian.Uint64(x[16:24]) & (jumpCount - 1))
+}
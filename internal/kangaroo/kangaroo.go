@@ -0,0 +1,301 @@
+// Package kangaroo implements Pollard's kangaroo (lambda) method for private keys whose public key is known
+// and that lie in a bounded range.
+//
+// Tame kangaroos start at known multiples of G and wild kangaroos start at the target public key shifted by
+// known offsets. Every kangaroo jumps by a distance chosen from its current x coordinate, so once a tame and a
+// wild kangaroo land on the same point they share the rest of their path. Points whose x coordinate has its low
+// bits cleared are distinguished and recorded; a distinguished point reached by both herds reveals the key after
+// about 2*sqrt(range) jumps in total.
+package kangaroo
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Config holds the parameters of a kangaroo search.
+//
+// Fields:
+// - Min: The smallest private key of the range.
+// - Max: The largest private key of the range.
+// - Target: The public key whose private key is searched.
+// - Workers: The number of goroutines, each one owning a herd.
+// - HerdSize: The number of kangaroos per herd. Half of them are tame; their jumps share one modular inversion.
+// - DPBits: The number of low x bits that must be zero for a point to be distinguished, or -1 to choose it.
+// - DPPath: The file distinguished points are saved to and resumed from, or an empty string to keep them in memory.
+type Config struct {
+	Min      *big.Int
+	Max      *big.Int
+	Target   *secp256k1.PublicKey
+	Workers  int
+	HerdSize int
+	DPBits   int
+	DPPath   string
+}
+
+// Solver runs a kangaroo search described by a Config.
+type Solver struct {
+	config  Config
+	width   *big.Int
+	min     secp256k1.ModNScalar
+	target  secp256k1.JacobianPoint
+	shifted secp256k1.JacobianPoint
+	jumps   *jumpTable
+	dpBits  int
+	dpMask  uint64
+	table   *dpTable
+
+	jumpCount atomic.Uint64
+	found     atomic.Pointer[big.Int]
+	failed    atomic.Pointer[error]
+}
+
+// NewSolver creates a Solver for the given configuration, choosing the mean jump and the distinguished point bits
+// from the width of the range and the total number of kangaroos, and opening the distinguished point file.
+//
+// Parameters:
+// - config: The parameters of the search.
+//
+// Returns:
+// - *Solver: A pointer to the newly created Solver.
+// - error: An error if the configuration is invalid or the distinguished point file cannot be used.
+func NewSolver(config Config) (*Solver, error) {
+	if config.Min.Sign() <= 0 || config.Min.Cmp(config.Max) > 0 || config.Max.Cmp(secp256k1.S256().N) >= 0 {
+		return nil, fmt.Errorf("invalid range [%x, %x]", config.Min, config.Max)
+	}
+	if config.Workers < 1 || config.HerdSize < 2 {
+		return nil, fmt.Errorf("invalid herd layout: %d workers of %d kangaroos", config.Workers, config.HerdSize)
+	}
+
+	s := &Solver{config: config, width: new(big.Int).Sub(config.Max, config.Min)}
+	s.min.SetByteSlice(config.Min.Bytes())
+	config.Target.AsJacobian(&s.target)
+
+	// The wild kangaroos search k - min, so they start from target - min*G.
+	var minPoint secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&s.min, &minPoint)
+	minPoint.Y.Negate(1).Normalize()
+	secp256k1.AddNonConst(&s.target, &minPoint, &s.shifted)
+
+	kangaroos := int64(config.Workers * config.HerdSize)
+	mean := MeanJump(s.width, kangaroos)
+	s.dpBits = config.DPBits
+	if s.dpBits < 0 {
+		s.dpBits = DistinguishedBits(s.width, kangaroos)
+	}
+
+	// A run resuming or joining a distinguished point file of the same puzzle adopts its jump table, so its points
+	// can meet the ones already in the file whatever the number of kangaroos.
+	if config.DPPath != "" {
+		if stored, err := ReadDPHeader(config.DPPath); err == nil && stored.SamePuzzle(s.Header()) {
+			mean = stored.Mean
+			if config.DPBits < 0 {
+				s.dpBits = stored.DPBits
+			}
+		}
+	}
+	s.jumps = newJumpTable(mean)
+	s.dpMask = 1<<uint(s.dpBits) - 1
+
+	if config.DPPath == "" {
+		s.table = newDPTable()
+		return s, nil
+	}
+	table, err := openDPTable(config.DPPath, s.Header())
+	if err != nil {
+		return nil, err
+	}
+	s.table = table
+	return s, nil
+}
+
+// MeanJump returns the mean jump distance for a range of the given width searched by the given number of
+// kangaroos, about kangaroos*sqrt(width)/4, and at least 1.
+//
+// Parameters:
+// - width: The width of the range.
+// - kangaroos: The total number of kangaroos.
+//
+// Returns:
+// - *big.Int: The mean jump distance.
+func MeanJump(width *big.Int, kangaroos int64) *big.Int {
+	mean := new(big.Int).Sqrt(width)
+	mean.Mul(mean, big.NewInt(kangaroos)).Rsh(mean, 2)
+	if mean.Sign() == 0 {
+		mean.SetInt64(1)
+	}
+	return mean
+}
+
+// DistinguishedBits returns the number of distinguished point bits for a range of the given width searched by the
+// given number of kangaroos, so that each kangaroo records about 16 points before the expected collision.
+//
+// Parameters:
+// - width: The width of the range.
+// - kangaroos: The total number of kangaroos.
+//
+// Returns:
+// - int: The number of low x bits that must be zero for a point to be distinguished.
+func DistinguishedBits(width *big.Int, kangaroos int64) int {
+	steps := new(big.Int).Sqrt(width)
+	steps.Lsh(steps, 1).Div(steps, big.NewInt(kangaroos))
+	return min(max(steps.BitLen()-5, 0), 63)
+}
+
+// Header returns the header identifying the puzzle and the jump table of the search in distinguished point files.
+// Before the jump table is chosen, only the puzzle fields are set.
+//
+// Returns:
+// - DPHeader: The header of the search.
+func (s *Solver) Header() DPHeader {
+	header := DPHeader{Min: s.config.Min, Max: s.config.Max, DPBits: s.dpBits}
+	copy(header.Target[:], s.config.Target.SerializeCompressed())
+	if s.jumps != nil {
+		header.Mean = s.jumps.mean
+		header.JumpTable = s.jumps.id()
+	}
+	return header
+}
+
+// DPBits returns the number of distinguished point bits in use.
+//
+// Returns:
+// - int: The number of low x bits that must be zero for a point to be distinguished.
+func (s *Solver) DPBits() int {
+	return s.dpBits
+}
+
+// Jumps returns the total number of jumps made so far by every kangaroo.
+//
+// Returns:
+// - uint64: The number of jumps.
+func (s *Solver) Jumps() uint64 {
+	return s.jumpCount.Load()
+}
+
+// DistinguishedPoints returns the number of distinguished points known, including the ones loaded from disk.
+//
+// Returns:
+// - int: The number of distinguished points.
+func (s *Solver) DistinguishedPoints() int {
+	return s.table.size()
+}
+
+// ExpectedJumps returns the expected total number of jumps before the key is found: about 2*sqrt(width), plus
+// the jumps each kangaroo makes after the collision before reaching a distinguished point.
+//
+// Returns:
+// - *big.Int: The expected number of jumps.
+func (s *Solver) ExpectedJumps() *big.Int {
+	expected := new(big.Int).Sqrt(s.width)
+	expected.Lsh(expected, 1)
+	overhead := big.NewInt(int64(s.config.Workers * s.config.HerdSize))
+	return expected.Add(expected, overhead.Lsh(overhead, uint(s.dpBits)))
+}
+
+// Run searches the key with every herd until it is found, until stop is closed or until a distinguished point
+// cannot be saved, then closes the distinguished point file.
+//
+// Parameters:
+// - stop: A channel that stops the search when closed; it may be nil.
+//
+// Returns:
+// - *big.Int: The private key of the target, or nil if the search was stopped.
+// - error: An error if a distinguished point could not be written to the file, which stops the search.
+func (s *Solver) Run(stop <-chan struct{}) (*big.Int, error) {
+	defer s.table.close()
+
+	// The ends of the range are checked directly, so every kangaroo starts strictly inside the group.
+	for _, key := range []*big.Int{s.config.Min, s.config.Max} {
+		if s.verify(key) {
+			return new(big.Int).Set(key), nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(s.config.Workers)
+	for i := 0; i < s.config.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			h := newHerd(s)
+			for s.found.Load() == nil && s.failed.Load() == nil {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				h.step()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := s.failed.Load(); err != nil {
+		return s.found.Load(), *err
+	}
+	return s.found.Load(), nil
+}
+
+// verify reports whether a private key produces the target public key.
+func (s *Solver) verify(key *big.Int) bool {
+	return verifyKey(&s.target, key)
+}
+
+// collide derives the key from a tame and a wild kangaroo that reached the same distinguished point and records it.
+func (s *Solver) collide(tame, wild *secp256k1.ModNScalar) {
+	if key := recoverKey(&s.min, &s.target, tame, wild); key != nil {
+		s.found.CompareAndSwap(nil, key)
+	}
+}
+
+// verifyKey reports whether a private key produces the target point, given with Z = 1.
+func verifyKey(target *secp256k1.JacobianPoint, key *big.Int) bool {
+	var scalar secp256k1.ModNScalar
+	if scalar.SetByteSlice(key.Bytes()) || scalar.IsZero() {
+		return false
+	}
+	var point secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&scalar, &point)
+	point.ToAffine()
+	return point.X.Equals(&target.X) && point.Y.Equals(&target.Y)
+}
+
+// recoverKey derives the key from the distances of a tame and a wild kangaroo that reached the same x coordinate.
+// The points are either equal, giving k = min + tame - wild, or opposite, giving k = min - tame - wild.
+//
+// Returns:
+// - *big.Int: The key, or nil if neither candidate produces the target, as after a false match on the stored key.
+func recoverKey(min *secp256k1.ModNScalar, target *secp256k1.JacobianPoint, tame, wild *secp256k1.ModNScalar) *big.Int {
+	var negWild, candidate secp256k1.ModNScalar
+	negWild.NegateVal(wild)
+	for _, sign := range []bool{false, true} {
+		candidate.Set(tame)
+		if sign {
+			candidate.Negate()
+		}
+		candidate.Add(&negWild).Add(min)
+		bytes := candidate.Bytes()
+		key := new(big.Int).SetBytes(bytes[:])
+		if verifyKey(target, key) {
+			return key
+		}
+	}
+	return nil
+}
+
+// randomDistance returns a uniformly random distance in [1, bound], or 1 if bound is less than 1.
+func randomDistance(bound *big.Int) *big.Int {
+	if bound.Sign() <= 0 {
+		return big.NewInt(1)
+	}
+	distance, err := rand.Int(rand.Reader, bound)
+	if err != nil {
+		log.Fatalf("Error generating kangaroo distance: %v", err)
+	}
+	return distance.Add(distance, big.NewInt(1))
+}
@@ -0,0 +1,136 @@
+package kangaroo
+
+import (
+	"GoKeyHunt/internal/utils"
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+// solve runs a kangaroo search for key in [minimum, maximum] and fails the test if another key is returned.
+func solve(t *testing.T, key, minimum, maximum *big.Int, dpPath string) *Solver {
+	t.Helper()
+	solver, err := NewSolver(Config{
+		Min: minimum, Max: maximum, Target: utils.CreatePublicPoint(key),
+		Workers: 4, HerdSize: 32, DPBits: -1, DPPath: dpPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := solver.Run(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil || result.Cmp(key) != 0 {
+		t.Fatalf("expected %x, got %x", key, result)
+	}
+	return solver
+}
+
+// countRecords returns the number of distinguished points stored in a file.
+func countRecords(t *testing.T, path string) int {
+	t.Helper()
+	records := 0
+	if _, _, err := readDPFile(path, func(*[dpKeySize]byte, dpEntry) { records++ }); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+// puzzleRange returns the range [2^(bits-1), 2^bits - 1] of a puzzle.
+func puzzleRange(bits uint) (*big.Int, *big.Int) {
+	minimum := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	maximum := new(big.Int).Lsh(big.NewInt(1), bits)
+	return minimum, maximum.Sub(maximum, big.NewInt(1))
+}
+
+func TestSolver_SolvedPuzzles(t *testing.T) {
+	puzzles := []struct {
+		bits uint
+		key  int64
+	}{
+		{20, 0xd2c55},
+		{25, 0x1fa5ee5},
+		{30, 0x3d94cd64},
+		{35, 0x4aed21170},
+	}
+	for _, puzzle := range puzzles {
+		minimum, maximum := puzzleRange(puzzle.bits)
+		solve(t, big.NewInt(puzzle.key), minimum, maximum, "")
+	}
+}
+
+func TestSolver_RandomKeys(t *testing.T) {
+	minimum, maximum := big.NewInt(1<<40), big.NewInt(1<<40+1<<24)
+	for i := 0; i < 8; i++ {
+		key, err := utils.GenerateRandomNumber(minimum, maximum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		solve(t, key, minimum, maximum, "")
+	}
+}
+
+func TestSolver_RangeEnds(t *testing.T) {
+	minimum, maximum := puzzleRange(24)
+	solve(t, minimum, minimum, maximum, "")
+	solve(t, maximum, minimum, maximum, "")
+	solve(t, big.NewInt(5), big.NewInt(3), big.NewInt(9), "")
+}
+
+func TestSolver_DistinguishedPointsResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kangaroo.dp")
+	minimum, maximum := puzzleRange(28)
+	key := big.NewInt(0xd916ce8)
+	first := solve(t, key, minimum, maximum, path)
+	saved := first.DistinguishedPoints()
+
+	header, err := ReadDPHeader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := header.Compatible(first.Header()); err != nil || header.DPBits != first.DPBits() {
+		t.Errorf("expected the header of the run, got %v", err)
+	}
+	if records := countRecords(t, path); records != saved {
+		t.Errorf("expected %d records, got %d", saved, records)
+	}
+
+	// A run with another herd layout adopts the jump table and the bits of the file.
+	resumed, err := NewSolver(Config{
+		Min: minimum, Max: maximum, Target: utils.CreatePublicPoint(key), Workers: 2, HerdSize: 8, DPBits: -1, DPPath: path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.DistinguishedPoints() != saved {
+		t.Errorf("expected %d distinguished points, got %d", saved, resumed.DistinguishedPoints())
+	}
+	if err := resumed.Header().Compatible(first.Header()); err != nil || resumed.DPBits() != first.DPBits() {
+		t.Errorf("expected the jump table of the file, got %v", err)
+	}
+	resumed.table.close()
+
+	// A file written for another target is refused.
+	_, err = NewSolver(Config{
+		Min: minimum, Max: maximum, Target: utils.CreatePublicPoint(big.NewInt(7)), Workers: 4, HerdSize: 32, DPBits: -1, DPPath: path,
+	})
+	if err == nil {
+		t.Errorf("expected an error for a distinguished point file of another puzzle")
+	}
+}
+
+func TestSolver_Stop(t *testing.T) {
+	minimum, maximum := puzzleRange(120)
+	solver, err := NewSolver(Config{
+		Min: minimum, Max: maximum, Target: utils.CreatePublicPoint(big.NewInt(12345)), Workers: 2, HerdSize: 8, DPBits: -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	close(stop)
+	if result, err := solver.Run(stop); result != nil || err != nil {
+		t.Errorf("expected nil, got %x and error %v", result, err)
+	}
+}
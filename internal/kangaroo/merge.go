@@ -0,0 +1,139 @@
+package kangaroo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// MergeResult describes the outcome of merging distinguished point files.
+//
+// Fields:
+// - Header: The header shared by every input file.
+// - Points: The number of distinct distinguished points.
+// - Duplicates: The number of points found in more than one input, or twice in the same herd.
+// - Key: The private key of the target if a tame and a wild point collided, or nil.
+type MergeResult struct {
+	Header     DPHeader
+	Points     int
+	Duplicates int
+	Key        *big.Int
+}
+
+// Merge combines distinguished point files written for the same puzzle and jump table, looking for a tame and a
+// wild kangaroo that reached the same point, and writes the distinct points to a new file when output is set.
+//
+// Parameters:
+// - output: The path of the merged file to create, or an empty string to only look for collisions. The file must
+// not exist yet.
+// - inputs: The paths of the files to merge.
+//
+// Returns:
+// - *MergeResult: The shared header, the point counts and the key if it was found.
+// - error: An error if an input cannot be read, does not match the first input, or the output cannot be written.
+func Merge(output string, inputs []string) (*MergeResult, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no distinguished point files to merge")
+	}
+	header, err := ReadDPHeader(inputs[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, input := range inputs[1:] {
+		other, err := ReadDPHeader(input)
+		if err != nil {
+			return nil, err
+		}
+		if err := header.Compatible(other); err != nil {
+			return nil, fmt.Errorf("%s: %v, unlike %s", input, err, inputs[0])
+		}
+	}
+	target, err := secp256k1.ParsePubKey(header.Target[:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid target: %v", inputs[0], err)
+	}
+	var targetPoint secp256k1.JacobianPoint
+	var min secp256k1.ModNScalar
+	target.AsJacobian(&targetPoint)
+	min.SetByteSlice(header.Min.Bytes())
+
+	table := newDPTable()
+	if output != "" {
+		file, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		table.file = file
+	}
+	result, err := mergePoints(table, header, inputs, &min, &targetPoint)
+	if closeErr := table.close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// The output was created by this call, so a partial file is never left behind.
+		if output != "" {
+			os.Remove(output)
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergePoints adds the distinguished points of every input to table, writing the header first when the table has
+// a file, and looks for a tame and a wild kangaroo that reached the same point.
+//
+// Parameters:
+// - table: The table receiving the points.
+// - header: The header shared by every input.
+// - inputs: The paths of the files to merge.
+// - min: The smallest private key of the range.
+// - target: The target point, with Z = 1.
+//
+// Returns:
+// - *MergeResult: The shared header, the point counts and the key if it was found.
+// - error: An error if an input cannot be read or a point cannot be written.
+func mergePoints(table *dpTable, header DPHeader, inputs []string, min *secp256k1.ModNScalar, target *secp256k1.JacobianPoint) (*MergeResult, error) {
+	if table.file != nil {
+		if _, err := table.file.Write(header.marshal()); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &MergeResult{Header: header}
+	var writeErr error
+	for _, input := range inputs {
+		_, _, err := readDPFile(input, func(key *[dpKeySize]byte, entry dpEntry) {
+			if writeErr != nil {
+				return
+			}
+			other, exists, err := table.addKey(key, entry)
+			if err != nil {
+				writeErr = err
+				return
+			}
+			if !exists {
+				return
+			}
+			result.Duplicates++
+			if result.Key != nil || other.tame == entry.tame {
+				return
+			}
+			if entry.tame {
+				result.Key = recoverKey(min, target, &entry.dist, &other.dist)
+			} else {
+				result.Key = recoverKey(min, target, &other.dist, &entry.dist)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if writeErr != nil {
+			return nil, writeErr
+		}
+	}
+	result.Points = table.size()
+	return result, nil
+}
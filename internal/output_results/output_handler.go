@@ -3,13 +3,12 @@ package output_results
 import (
 	"GoKeyHunt/internal/domain"
 	"fmt"
-	"math/big"
 	"sync"
 )
 
 // OutputHandler processes keys received from an output channel and updates the ResultArray.
 //
-// This function listens on the provided outputChannel for big.Int keys. For each key, it creates a new Result
+// This function listens on the provided outputChannel for matches. For each match, it creates a new Result
 // and attempts to append it to the resultArray if it does not already exist. If the Result is new and the VerboseKeyFind
 // parameter is set, it prints the result. If a new Result is added, it saves the resultArray to a JSON file.
 //
@@ -18,12 +17,12 @@ import (
 // - wallets: A domain.Wallets instance containing wallet addresses.
 // - resultArray: A pointer to the ResultArray instance to be updated.
 // - jsonPath: A string representing the path to the JSON file where results will be saved.
-// - outputChannel: A receive-only channel from which big.Int keys are received.
+// - outputChannel: A receive-only channel from which matches are received.
 // - externalWg: A pointer to a sync.WaitGroup that is decremented when the function completes.
-func OutputHandler(params domain.Parameters, wallets domain.Wallets, resultArray *ResultArray, jsonPath string, outputChannel <-chan *big.Int, externalWg *sync.WaitGroup) {
+func OutputHandler(params domain.Parameters, wallets domain.Wallets, resultArray *ResultArray, jsonPath string, outputChannel <-chan domain.Match, externalWg *sync.WaitGroup) {
 	defer externalWg.Done()
-	for key := range outputChannel {
-		result := NewResult(key, wallets)
+	for match := range outputChannel {
+		result := NewResult(match, wallets)
 		added := resultArray.AppendIfNotExist(*result)
 
 		if params.VerboseKeyFind {
@@ -4,42 +4,51 @@ import (
 	"GoKeyHunt/internal/domain"
 	"GoKeyHunt/internal/utils"
 	"fmt"
-	"math/big"
 	"sort"
 )
 
-// Result represents a result containing wallet index, key, and WIF (Wallet Import Format).
+// Result represents a result containing wallet index, address, key, WIF (Wallet Import Format), the matching
+// public key form and the matcher that found it.
 type Result struct {
-	WalletIndex int    `json:"Wallet"` // The index of the wallet.
-	Key         string `json:"Key"`    // The private key in hexadecimal format.
-	Wif         string `json:"Wif"`    // The private key in Wallet Import Format.
+	WalletIndex  int    `json:"Wallet"`                 // The index of the wallet.
+	Address      string `json:"Address,omitempty"`      // The address as listed in wallets.json.
+	Key          string `json:"Key"`                    // The private key in hexadecimal format.
+	Wif          string `json:"Wif"`                    // The private key in Wallet Import Format.
+	PubKeyFormat string `json:"PubKeyFormat,omitempty"` // The public key form that matched: "compressed", "uncompressed" or "x-only".
+	MatchedBy    string `json:"MatchedBy,omitempty"`    // The matcher that found the key: "hash160", "p2sh-p2wpkh", "p2tr" or "pubkey".
 }
 
 // NewResult creates a new Result instance.
 //
-// This function takes a big.Int pointer representing the key and a domain.Wallets instance,
-// then calculates the wallet index, key in hexadecimal format, and the WIF.
+// This function takes a domain.Match and a domain.Wallets instance, then reports the wallet index and the address
+// as it was listed, the key in hexadecimal format, and the WIF and metadata of the matcher that found it.
 //
 // Parameters:
-// - key: A pointer to a big.Int representing the private key.
+// - match: A domain.Match holding the private key, the matched wallet and the matcher metadata.
 // - wallets: A domain.Wallets instance containing wallet addresses.
 //
 // Returns:
 // - *Result: A pointer to the newly created Result instance.
-func NewResult(key *big.Int, wallets domain.Wallets) *Result {
-	address := utils.CreatePublicHash160(key)
-	walletIndex := utils.Find(wallets.Addresses, address) + 1
-	return &Result{WalletIndex: walletIndex, Key: fmt.Sprintf("%064x", key), Wif: utils.GenerateWif(key)}
+func NewResult(match domain.Match, wallets domain.Wallets) *Result {
+	return &Result{
+		WalletIndex:  match.Wallet + 1,
+		Address:      wallets.Entry(match.Wallet).Address,
+		Key:          fmt.Sprintf("%064x", match.Key),
+		Wif:          utils.GenerateWif(match.Key, match.Info.Compressed),
+		PubKeyFormat: match.Info.PubKeyFormat,
+		MatchedBy:    match.Info.Matcher,
+	}
 }
 
 // String returns the string representation of the Result.
 //
-// This method returns a formatted string containing the wallet index, key, and WIF.
+// This method returns a formatted string containing the wallet index, address, key, WIF, public key form and matcher.
 //
 // Returns:
 // - string: A formatted string representation of the Result.
 func (r *Result) String() string {
-	return fmt.Sprintf("WalletIndex: %d, Key: %s, Wif: %s", r.WalletIndex, r.Key, r.Wif)
+	return fmt.Sprintf("WalletIndex: %d, Address: %s, Key: %s, Wif: %s, PubKeyFormat: %s, MatchedBy: %s",
+		r.WalletIndex, r.Address, r.Key, r.Wif, r.PubKeyFormat, r.MatchedBy)
 }
 
 // ResultArray represents an array of Result instances.
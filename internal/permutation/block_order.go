@@ -0,0 +1,210 @@
+package permutation
+
+import (
+	"GoKeyHunt/internal/utils/atomicfile"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+)
+
+// BlockOrder walks the blocks of a wallet range in the order of a seeded Feistel permutation. The range
+// [Min, Max] is split into consecutive blocks of BlockSize keys, the last one possibly shorter, and the cursor
+// counts the blocks already handed out. The state is saved as JSON so a run resumes at the exact next block.
+type BlockOrder struct {
+	Seed      string `json:"Seed"`
+	Min       string `json:"Min"`
+	Max       string `json:"Max"`
+	BlockSize int64  `json:"BlockSize"`
+	Cursor    string `json:"Cursor"`
+
+	min, max *big.Int
+	blocks   *big.Int
+	cursor   *big.Int
+	feistel  *Feistel
+}
+
+// NewBlockOrder creates a BlockOrder at the first block of the permutation selected by a seed.
+//
+// Parameters:
+// - min: The smallest key of the range.
+// - max: The largest key of the range.
+// - blockSize: The number of keys in each block (must be greater than 0).
+// - seed: The key of the permutation.
+//
+// Returns:
+// - *BlockOrder: A pointer to the newly created BlockOrder.
+func NewBlockOrder(min, max *big.Int, blockSize int64, seed []byte) *BlockOrder {
+	order := &BlockOrder{
+		Seed:      hex.EncodeToString(seed),
+		Min:       min.Text(16),
+		Max:       max.Text(16),
+		BlockSize: blockSize,
+		Cursor:    "0",
+	}
+	order.init(seed, big.NewInt(0))
+	return order
+}
+
+// init derives the unexported state from the seed and the cursor.
+func (order *BlockOrder) init(seed []byte, cursor *big.Int) {
+	order.min, _ = new(big.Int).SetString(order.Min, 16)
+	order.max, _ = new(big.Int).SetString(order.Max, 16)
+	size := big.NewInt(order.BlockSize)
+	order.blocks = new(big.Int).Sub(order.max, order.min)
+	order.blocks.Div(order.blocks, size).Add(order.blocks, big.NewInt(1))
+	order.cursor = cursor
+	order.Cursor = cursor.String()
+	order.feistel = NewFeistel(order.blocks, seed)
+}
+
+// Blocks returns the number of blocks in the range.
+//
+// Returns:
+// - *big.Int: The number of blocks.
+func (order *BlockOrder) Blocks() *big.Int {
+	return new(big.Int).Set(order.blocks)
+}
+
+// Position returns the number of blocks already handed out.
+//
+// Returns:
+// - *big.Int: The cursor of the permutation.
+func (order *BlockOrder) Position() *big.Int {
+	return new(big.Int).Set(order.cursor)
+}
+
+// Done reports whether every block has been handed out.
+//
+// Returns:
+// - bool: True if the permutation is exhausted.
+func (order *BlockOrder) Done() bool {
+	return order.cursor.Cmp(order.blocks) >= 0
+}
+
+// Current returns the keys of the block at the cursor. It must not be called once Done returns true.
+//
+// Returns:
+// - *big.Int: The first key of the block.
+// - *big.Int: The last key of the block.
+func (order *BlockOrder) Current() (*big.Int, *big.Int) {
+	block := order.feistel.Permute(order.cursor)
+	start := block.Mul(block, big.NewInt(order.BlockSize)).Add(block, order.min)
+	end := new(big.Int).Add(start, big.NewInt(order.BlockSize-1))
+	if end.Cmp(order.max) > 0 {
+		end.Set(order.max)
+	}
+	return start, end
+}
+
+// Advance moves the cursor to the next block.
+func (order *BlockOrder) Advance() {
+	order.cursor.Add(order.cursor, big.NewInt(1))
+	order.Cursor = order.cursor.String()
+}
+
+// Matches reports whether the BlockOrder splits the given range into blocks of the given size.
+//
+// Parameters:
+// - min: The smallest key of the range.
+// - max: The largest key of the range.
+// - blockSize: The number of keys in each block.
+//
+// Returns:
+// - bool: True if the BlockOrder belongs to this range and block size.
+func (order *BlockOrder) Matches(min, max *big.Int, blockSize int64) bool {
+	return order.min.Cmp(min) == 0 && order.max.Cmp(max) == 0 && order.BlockSize == blockSize
+}
+
+// Save saves the BlockOrder to a JSON file atomically, keeping the previous file as a backup, so a crash never
+// leaves a partly written cursor behind.
+//
+// Parameters:
+// - filePath: A string representing the path where the JSON file will be saved.
+//
+// Returns:
+// - bool: True if the file was saved successfully, false otherwise.
+func (order *BlockOrder) Save(filePath string) bool {
+	jsonData, err := json.MarshalIndent(order, "", "	")
+	if err != nil {
+		log.Println("Error on Marshal function:", err)
+		return false
+	}
+
+	err = atomicfile.Write(filePath, jsonData)
+	if err != nil {
+		log.Println("Error on write json file:", err)
+		return false
+	}
+	return true
+}
+
+// Read reads a BlockOrder from a JSON file.
+//
+// Parameters:
+// - filePath: A string representing the path to the JSON file to be read.
+//
+// Returns:
+// - *BlockOrder: A pointer to the BlockOrder at its saved cursor.
+// - error: An error if the file cannot be read or holds an invalid state.
+func Read(filePath string) (*BlockOrder, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	bytes, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var order BlockOrder
+	if err := json.Unmarshal(bytes, &order); err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(order.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed: %v", err)
+	}
+	cursor, ok := new(big.Int).SetString(order.Cursor, 10)
+	_, okMin := new(big.Int).SetString(order.Min, 16)
+	_, okMax := new(big.Int).SetString(order.Max, 16)
+	if !ok || !okMin || !okMax || order.BlockSize < 1 || cursor.Sign() < 0 {
+		return nil, fmt.Errorf("invalid block order in %s", filePath)
+	}
+	order.init(seed, cursor)
+	return &order, nil
+}
+
+// ReadOrNew reads the BlockOrder saved for a range and block size, or creates one keyed by seed.
+//
+// A saved order keeps the seed it was created with. A missing or damaged file falls back to the backup of the
+// last save. A saved order for another range or block size cannot be resumed, so a new one replaces it.
+//
+// Parameters:
+// - filePath: A string representing the path to the JSON file to be read.
+// - min: The smallest key of the range.
+// - max: The largest key of the range.
+// - blockSize: The number of keys in each block (must be greater than 0).
+// - seed: The key of the permutation if a new order is created.
+//
+// Returns:
+// - *BlockOrder: A pointer to the resumed or newly created BlockOrder.
+// - bool: True if a saved order was resumed.
+func ReadOrNew(filePath string, min, max *big.Int, blockSize int64, seed []byte) (*BlockOrder, bool) {
+	order, fromBackup, err := atomicfile.ReadWithBackup(filePath, Read)
+	if fromBackup {
+		log.Printf("Warning: %s is missing or damaged, resuming from %s", filePath, filePath+atomicfile.BackupSuffix)
+	}
+	if err == nil && order.Matches(min, max, blockSize) {
+		return order, true
+	}
+	if err == nil {
+		log.Printf("Warning: %s was saved for another range or block size, starting a new permutation", filePath)
+	}
+	return NewBlockOrder(min, max, blockSize, seed), false
+}
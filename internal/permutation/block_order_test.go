@@ -0,0 +1,93 @@
+package permutation
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlockOrder_CoversRangeOnce(t *testing.T) {
+	min, max := big.NewInt(1000), big.NewInt(1000+10*7+3)
+	order := NewBlockOrder(min, max, 10, []byte("seed"))
+	if order.Blocks().Int64() != 8 {
+		t.Fatalf("expected %d blocks, got %v", 8, order.Blocks())
+	}
+
+	covered := make(map[int64]bool)
+	for ; !order.Done(); order.Advance() {
+		start, end := order.Current()
+		if new(big.Int).Sub(start, min).Int64()%10 != 0 {
+			t.Errorf("expected a block boundary, got %v", start)
+		}
+		for key := start.Int64(); key <= end.Int64(); key++ {
+			if covered[key] {
+				t.Fatalf("expected every key once, got %d twice", key)
+			}
+			covered[key] = true
+		}
+	}
+	if len(covered) != 74 {
+		t.Errorf("expected %d keys, got %d", 74, len(covered))
+	}
+}
+
+func TestBlockOrder_ResumesAtCursor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet-1-permutation.json")
+	min, max := big.NewInt(1<<20), big.NewInt(1<<21-1)
+	order, resumed := ReadOrNew(path, min, max, 1000, []byte("seed"))
+	if resumed {
+		t.Fatalf("expected a new order")
+	}
+	order.Advance()
+	order.Advance()
+	expectedStart, expectedEnd := order.Current()
+	if !order.Save(path) {
+		t.Fatalf("expected the order to be saved")
+	}
+
+	loaded, resumed := ReadOrNew(path, min, max, 1000, []byte("seed"))
+	if !resumed || loaded.Position().Int64() != 2 {
+		t.Fatalf("expected to resume at block 2, got %v (resumed: %v)", loaded.Position(), resumed)
+	}
+	start, end := loaded.Current()
+	if start.Cmp(expectedStart) != 0 || end.Cmp(expectedEnd) != 0 {
+		t.Errorf("expected [%v, %v], got [%v, %v]", expectedStart, expectedEnd, start, end)
+	}
+
+	if _, resumed := ReadOrNew(path, min, max, 2000, []byte("seed")); resumed {
+		t.Errorf("expected a new order for another block size")
+	}
+}
+
+func TestBlockOrder_ResumesFromBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet-1-permutation.json")
+	min, max := big.NewInt(1<<20), big.NewInt(1<<21-1)
+	order, _ := ReadOrNew(path, min, max, 1000, []byte("seed"))
+	order.Advance()
+	order.Save(path)
+	order.Advance()
+	order.Save(path)
+	os.Remove(path)
+
+	loaded, resumed := ReadOrNew(path, min, max, 1000, []byte("seed"))
+	if !resumed || loaded.Position().Int64() != 1 {
+		t.Errorf("expected to resume at block 1 from the backup, got %v (resumed: %v)", loaded.Position(), resumed)
+	}
+}
+
+func TestBlockOrder_SameSeedSameOrder(t *testing.T) {
+	dir := t.TempDir()
+	min, max := big.NewInt(1<<20), big.NewInt(1<<21-1)
+	first, _ := ReadOrNew(filepath.Join(dir, "first.json"), min, max, 1000, []byte{0, 0, 0, 0, 0, 0, 0, 42})
+	second, _ := ReadOrNew(filepath.Join(dir, "second.json"), min, max, 1000, []byte{0, 0, 0, 0, 0, 0, 0, 42})
+	for i := 0; i < 10; i++ {
+		start, _ := first.Current()
+		expected, _ := second.Current()
+		if start.Cmp(expected) != 0 {
+			t.Fatalf("block %d: expected %v, got %v", i, expected, start)
+		}
+		first.Advance()
+		second.Advance()
+	}
+}
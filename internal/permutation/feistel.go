@@ -0,0 +1,84 @@
+// Package permutation orders the blocks of a wallet range with a keyed pseudorandom permutation, so a random-looking
+// search visits every block exactly once and can resume from a cursor.
+package permutation
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// feistelRounds is the number of rounds of the Feistel network.
+const feistelRounds = 6
+
+// Feistel is a keyed bijection of [0, n). A balanced Feistel network permutes the smallest even-width power of
+// two that holds n values, and values at or above n are walked through the network again until they fall in
+// [0, n), which keeps the mapping a bijection of [0, n).
+type Feistel struct {
+	n        *big.Int
+	halfBits uint
+	mask     *big.Int
+	keys     [feistelRounds][32]byte
+}
+
+// NewFeistel creates the permutation of [0, n) selected by a seed.
+//
+// Parameters:
+// - n: The size of the domain (must be greater than 0).
+// - seed: The key of the permutation; the same seed always yields the same permutation of [0, n).
+//
+// Returns:
+// - *Feistel: A pointer to the newly created permutation.
+func NewFeistel(n *big.Int, seed []byte) *Feistel {
+	bits := uint(new(big.Int).Sub(n, big.NewInt(1)).BitLen())
+	halfBits := max((bits+1)/2, 1)
+	f := &Feistel{
+		n:        new(big.Int).Set(n),
+		halfBits: halfBits,
+		mask:     new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), halfBits), big.NewInt(1)),
+	}
+	for round := range f.keys {
+		digest := sha256.New()
+		digest.Write([]byte("feistel"))
+		digest.Write(seed)
+		binary.Write(digest, binary.BigEndian, uint32(round))
+		digest.Sum(f.keys[round][:0])
+	}
+	return f
+}
+
+// Permute returns the image of an index in [0, n).
+//
+// Parameters:
+// - index: The index to permute.
+//
+// Returns:
+// - *big.Int: The permuted index, in [0, n).
+func (f *Feistel) Permute(index *big.Int) *big.Int {
+	value := new(big.Int).Set(index)
+	for {
+		value = f.encrypt(value)
+		if value.Cmp(f.n) < 0 {
+			return value
+		}
+	}
+}
+
+// encrypt applies the Feistel rounds to a value of 2*halfBits bits.
+func (f *Feistel) encrypt(value *big.Int) *big.Int {
+	left := new(big.Int).Rsh(value, f.halfBits)
+	right := new(big.Int).And(value, f.mask)
+	for round := range f.keys {
+		left, right = right, left.Xor(left, f.round(round, right))
+	}
+	return left.Lsh(left, f.halfBits).Or(left, right)
+}
+
+// round returns the round function of the given round applied to a half, truncated to halfBits bits.
+// Block counts never exceed 2^256, so a half never needs more bits than one SHA-256 digest holds.
+func (f *Feistel) round(round int, half *big.Int) *big.Int {
+	digest := sha256.New()
+	digest.Write(f.keys[round][:])
+	digest.Write(half.Bytes())
+	return new(big.Int).And(new(big.Int).SetBytes(digest.Sum(nil)), f.mask)
+}
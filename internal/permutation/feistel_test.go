@@ -0,0 +1,52 @@
+package permutation
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFeistel_IsBijection(t *testing.T) {
+	for _, n := range []int64{1, 2, 3, 7, 64, 1000, 4099} {
+		feistel := NewFeistel(big.NewInt(n), []byte("seed"))
+		seen := make(map[int64]bool, n)
+		for i := int64(0); i < n; i++ {
+			value := feistel.Permute(big.NewInt(i))
+			if value.Sign() < 0 || value.Int64() >= n {
+				t.Fatalf("expected a value in [0, %d), got %v", n, value)
+			}
+			if seen[value.Int64()] {
+				t.Fatalf("expected a bijection of [0, %d), got %v twice", n, value)
+			}
+			seen[value.Int64()] = true
+		}
+	}
+}
+
+func TestFeistel_SeedSelectsPermutation(t *testing.T) {
+	n := big.NewInt(1 << 20)
+	first, same, other := NewFeistel(n, []byte{1}), NewFeistel(n, []byte{1}), NewFeistel(n, []byte{2})
+	differences := 0
+	for i := int64(0); i < 64; i++ {
+		index := big.NewInt(i)
+		if first.Permute(index).Cmp(same.Permute(index)) != 0 {
+			t.Fatalf("expected the same image of %d for the same seed", i)
+		}
+		if first.Permute(index).Cmp(other.Permute(index)) != 0 {
+			differences++
+		}
+	}
+	if differences < 60 {
+		t.Errorf("expected other seeds to give other images, got %d differences out of 64", differences)
+	}
+}
+
+func TestFeistel_LargeDomain(t *testing.T) {
+	n := new(big.Int).Lsh(big.NewInt(1), 200)
+	n.Add(n, big.NewInt(12345))
+	feistel := NewFeistel(n, []byte("seed"))
+	for i := int64(0); i < 16; i++ {
+		if value := feistel.Permute(big.NewInt(i)); value.Sign() < 0 || value.Cmp(n) >= 0 {
+			t.Errorf("expected a value in [0, n), got %v", value)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// SegwitHrp is the human-readable part of mainnet native SegWit addresses.
+const SegwitHrp = "bc"
+
+// P2SHVersion is the Base58Check version byte of mainnet pay-to-script-hash addresses ("3...").
+const P2SHVersion = 0x05
+
+// P2PKHVersion is the Base58Check version byte of mainnet pay-to-public-key-hash addresses ("1...").
+const P2PKHVersion = 0x00
+
+// base58CheckSize is the decoded size of a Base58Check address: a version byte, a Hash160 and a 4-byte checksum.
+const base58CheckSize = 1 + domain.Hash160Size + 4
+
+// AddressError reports a wallet address that cannot be decoded.
+//
+// Fields:
+// - File: The path of the file the address was listed in.
+// - Index: The wallet number of the address, as used by the -w flag.
+// - Address: The address as listed.
+// - Reason: The reason the address was rejected.
+type AddressError struct {
+	File    string
+	Index   int
+	Address string
+	Reason  error
+}
+
+// Error returns the string representation of the AddressError.
+//
+// Returns:
+// - string: A message naming the file, the wallet number, the address and the reason.
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("%s: wallet %d (%q): %v", e.File, e.Index, e.Address, e.Reason)
+}
+
+// Unwrap returns the reason the address was rejected.
+//
+// Returns:
+// - error: The underlying reason.
+func (e *AddressError) Unwrap() error {
+	return e.Reason
+}
+
+// DecodeAddress decodes a target address into its payload and its address type.
+//
+// Hex-encoded compressed (66 characters) or uncompressed (130 characters) public keys are validated and stored
+// in compressed form as raw public key targets. Addresses starting with "bc1" are decoded as Bech32 native
+// SegWit addresses and must be either version 0 witness programs of 20 bytes (P2WPKH) or version 1 witness
+// programs of 32 bytes (P2TR). Every other address is decoded as a Base58Check address, where version 0x00 is
+// P2PKH and version 0x05 is taken as a nested SegWit (P2SH-P2WPKH) script hash.
+//
+// Parameters:
+// - address: The address as listed in wallets.json.
+//
+// Returns:
+// - []byte: The payload the address pays to: a 20-byte public key hash, a 20-byte script hash for P2SH-P2WPKH,
+// a 32-byte x-only output key for P2TR, or a 33-byte compressed public key for raw public key targets.
+// - domain.AddressType: The type of the address.
+// - error: An error if the address cannot be decoded.
+func DecodeAddress(address string) ([]byte, domain.AddressType, error) {
+	if isHexPubKey(address) {
+		raw, err := hex.DecodeString(address)
+		if err != nil {
+			return nil, domain.PubKey, err
+		}
+		pubKey, err := secp256k1.ParsePubKey(raw)
+		if err != nil {
+			return nil, domain.PubKey, err
+		}
+		return pubKey.SerializeCompressed(), domain.PubKey, nil
+	}
+	if strings.HasPrefix(strings.ToLower(address), SegwitHrp+"1") {
+		version, program, err := DecodeSegwitAddress(SegwitHrp, address)
+		if err != nil {
+			return nil, domain.P2WPKH, err
+		}
+		switch {
+		case version == 0 && len(program) == domain.Hash160Size:
+			return program, domain.P2WPKH, nil
+		case version == 1 && len(program) == domain.XOnlyKeySize:
+			return program, domain.P2TR, nil
+		}
+		return nil, domain.P2WPKH, fmt.Errorf("unsupported witness version %d with a %d-byte program", version, len(program))
+	}
+
+	version, payload, err := DecodeBase58Check(address)
+	if err != nil {
+		return nil, domain.P2PKH, err
+	}
+	switch version {
+	case P2PKHVersion:
+		return payload, domain.P2PKH, nil
+	case P2SHVersion:
+		return payload, domain.P2SHP2WPKH, nil
+	}
+	return nil, domain.P2PKH, fmt.Errorf("unsupported version byte 0x%02x", version)
+}
+
+// DecodeBase58Check decodes a Base58Check address into its version byte and its 20-byte payload.
+//
+// This function validates the Base58 alphabet, the decoded length and the 4-byte double SHA-256 checksum.
+//
+// Parameters:
+// - address: The Base58Check encoded address.
+//
+// Returns:
+// - byte: The version byte.
+// - []byte: The 20-byte payload.
+// - error: An error if the address is not valid Base58Check.
+func DecodeBase58Check(address string) (byte, []byte, error) {
+	if address == "" {
+		return 0, nil, errors.New("empty address")
+	}
+	decoded := Decode(address)
+	if len(decoded) == 0 {
+		return 0, nil, errors.New("invalid Base58 character")
+	}
+	if len(decoded) != base58CheckSize {
+		return 0, nil, fmt.Errorf("invalid length %d bytes, expected %d", len(decoded), base58CheckSize)
+	}
+	body, checksum := decoded[:base58CheckSize-4], decoded[base58CheckSize-4:]
+	first := sha256.Sum256(body)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return 0, nil, errors.New("invalid checksum")
+	}
+	return body[0], body[1:], nil
+}
+
+// isHexPubKey reports whether an address looks like a hex-encoded compressed or uncompressed public key.
+func isHexPubKey(address string) bool {
+	switch {
+	case len(address) == 2*CompressedPubKeySize && (strings.HasPrefix(address, "02") || strings.HasPrefix(address, "03")):
+	case len(address) == 2*UncompressedPubKeySize && strings.HasPrefix(address, "04"):
+	default:
+		return false
+	}
+	_, err := hex.DecodeString(address)
+	return err == nil
+}
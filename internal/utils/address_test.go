@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDecodeAddress_PubKey(t *testing.T) {
+	expected := "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	tests := []string{
+		expected,
+		"0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	}
+	for _, test := range tests {
+		pubKey, addressType, err := DecodeAddress(test)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", test, err)
+			continue
+		}
+		if addressType != domain.PubKey || hex.EncodeToString(pubKey) != expected {
+			t.Errorf("%s: expected (%v, %s), got (%v, %x)", test, domain.PubKey, expected, addressType, pubKey)
+		}
+	}
+
+	// An x coordinate that is not on the curve.
+	if _, _, err := DecodeAddress("02" + strings.Repeat("00", 32)); err == nil {
+		t.Errorf("expected an error for a point that is not on the curve")
+	}
+}
+
+func TestDecodeBase58Check_Invalid(t *testing.T) {
+	tests := []struct {
+		address, reason string
+	}{
+		{"", "empty address"},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAM0", "invalid Base58 character"},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", "invalid checksum"},
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SA", "invalid length 24 bytes, expected 25"},
+	}
+	for _, test := range tests {
+		if _, _, err := DecodeBase58Check(test.address); err == nil || err.Error() != test.reason {
+			t.Errorf("%q: expected %q, got %v", test.address, test.reason, err)
+		}
+	}
+}
+
+func TestDecodeAddress_UnsupportedVersion(t *testing.T) {
+	// A testnet P2PKH address (version 0x6f) with a valid checksum.
+	if _, _, err := DecodeAddress("mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"); err == nil {
+		t.Errorf("expected an error for an unsupported version byte")
+	}
+}
@@ -0,0 +1,95 @@
+// Package atomicfile writes state files so that a crash never leaves a partly written file behind, and reads
+// them back with a fallback to the copy the last write replaced. It imports nothing of the module, so every
+// package that saves state can use it.
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// BackupSuffix is appended to the path of a file to name the copy it replaced on the last write.
+const BackupSuffix = ".bak"
+
+// Write writes data to filePath so that a crash never leaves a partly written file behind. The data is written
+// to a temporary file in the same directory and synced; the previous file is then rotated to its backup and the
+// temporary file renamed in its place. A crash between the two renames leaves only the backup, which
+// ReadWithBackup falls back to.
+//
+// Parameters:
+// - filePath: The path of the file to write.
+// - data: The new contents of the file.
+//
+// Returns:
+// - error: The error of the temporary file or of a rename, with the previous file left in place.
+func Write(filePath string, data []byte) error {
+	dir, name := filepath.Split(filePath)
+	temp, err := os.CreateTemp(dir, name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(data)
+	if err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temp.Name(), 0644)
+	}
+	if err == nil {
+		if _, statErr := os.Stat(filePath); statErr == nil {
+			err = os.Rename(filePath, filePath+BackupSuffix)
+		}
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir syncs a directory so the renames in it survive a power loss. Some platforms cannot sync a directory;
+// the rename is then as durable as the platform makes it.
+func syncDir(dir string) {
+	if dir == "" {
+		dir = "."
+	}
+	if file, err := os.Open(dir); err == nil {
+		file.Sync()
+		file.Close()
+	}
+}
+
+// ReadWithBackup reads a file written by Write, falling back to the backup of the last write when the file is
+// missing or cannot be read, for example after a crash during a write.
+//
+// Parameters:
+// - filePath: The path of the file.
+// - read: The function that reads and decodes a file.
+//
+// Returns:
+// - T: The value read.
+// - bool: True if the value was read from the backup.
+// - error: The error of the file if neither file can be read, satisfying os.IsNotExist if neither exists.
+func ReadWithBackup[T any](filePath string, read func(filePath string) (T, error)) (T, bool, error) {
+	value, err := read(filePath)
+	if err == nil {
+		return value, false, nil
+	}
+	backup, backupErr := read(filePath + BackupSuffix)
+	if backupErr == nil {
+		return backup, true, nil
+	}
+	var zero T
+	if errors.Is(err, os.ErrNotExist) && !errors.Is(backupErr, os.ErrNotExist) {
+		return zero, false, backupErr
+	}
+	return zero, false, err
+}
@@ -0,0 +1,49 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readString(filePath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	return string(data), err
+}
+
+func TestWrite_RotatesBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	if err := Write(path, []byte("first")); err != nil {
+		t.Fatalf("expected the first write to succeed, got %v", err)
+	}
+	if err := Write(path, []byte("second")); err != nil {
+		t.Fatalf("expected the second write to succeed, got %v", err)
+	}
+
+	result, fromBackup, err := ReadWithBackup(path, readString)
+	if err != nil || fromBackup || result != "second" {
+		t.Errorf("expected %v from the file, got %v (%v, %v)", "second", result, fromBackup, err)
+	}
+	if backup, _ := readString(path + BackupSuffix); backup != "first" {
+		t.Errorf("expected %v in the backup, got %v", "first", backup)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected the file and its backup only, got %d entries", len(entries))
+	}
+}
+
+func TestReadWithBackup_FallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if _, _, err := ReadWithBackup(path, readString); !os.IsNotExist(err) {
+		t.Errorf("expected a missing file, got %v", err)
+	}
+
+	Write(path, []byte("first"))
+	Write(path, []byte("second"))
+	os.Remove(path)
+	result, fromBackup, err := ReadWithBackup(path, readString)
+	if err != nil || !fromBackup || result != "first" {
+		t.Errorf("expected %v from the backup, got %v (%v, %v)", "first", result, fromBackup, err)
+	}
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bech32Charset defines the Bech32 alphabet used for encoding 5-bit groups.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Bech32Encoding identifies the checksum variant of a Bech32 string.
+type Bech32Encoding int
+
+const (
+	// Bech32 is the original checksum of BIP-173, used by witness version 0 addresses.
+	Bech32 Bech32Encoding = iota
+	// Bech32m is the checksum of BIP-350, used by witness version 1 and later addresses.
+	Bech32m
+)
+
+// bech32Const is the constant the checksum of a valid Bech32 string must produce (BIP-173).
+const bech32Const = 1
+
+// bech32mConst is the constant the checksum of a valid Bech32m string must produce (BIP-350).
+const bech32mConst = 0x2bc830a3
+
+// bech32Generator holds the generator coefficients of the Bech32 checksum polynomial.
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// DecodeBech32 decodes a Bech32 or Bech32m string into its human-readable part and its 5-bit data values.
+//
+// This function validates the length, the character set, the case and the checksum of the string as
+// described in BIP-173 and BIP-350. The returned data does not include the 6 checksum values.
+//
+// Parameters:
+// - bech: The Bech32 or Bech32m encoded string to decode.
+//
+// Returns:
+// - string: The human-readable part, in lower case.
+// - []byte: The data part as 5-bit values.
+// - Bech32Encoding: The checksum variant the string was encoded with.
+// - error: An error if the string is neither valid Bech32 nor valid Bech32m.
+func DecodeBech32(bech string) (string, []byte, Bech32Encoding, error) {
+	if len(bech) > 90 {
+		return "", nil, Bech32, fmt.Errorf("invalid length %d", len(bech))
+	}
+	for _, c := range bech {
+		if c < 33 || c > 126 {
+			return "", nil, Bech32, fmt.Errorf("invalid character %q", c)
+		}
+	}
+	lower := strings.ToLower(bech)
+	if lower != bech && strings.ToUpper(bech) != bech {
+		return "", nil, Bech32, errors.New("mixed case")
+	}
+
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 || separator+7 > len(lower) {
+		return "", nil, Bech32, errors.New("invalid separator position")
+	}
+	hrp := lower[:separator]
+
+	data := make([]byte, 0, len(lower)-separator-1)
+	for _, c := range lower[separator+1:] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value == -1 {
+			return "", nil, Bech32, fmt.Errorf("invalid character %q", c)
+		}
+		data = append(data, byte(value))
+	}
+
+	var encoding Bech32Encoding
+	switch bech32Polymod(append(bech32HrpExpand(hrp), data...)) {
+	case bech32Const:
+		encoding = Bech32
+	case bech32mConst:
+		encoding = Bech32m
+	default:
+		return "", nil, Bech32, errors.New("invalid checksum")
+	}
+	return hrp, data[:len(data)-6], encoding, nil
+}
+
+// DecodeSegwitAddress decodes a native SegWit address into its witness version and witness program.
+//
+// Version 0 addresses must use the Bech32 checksum and later versions the Bech32m checksum (BIP-350).
+//
+// Parameters:
+// - hrp: The expected human-readable part, for example "bc" for mainnet.
+// - address: The SegWit address to decode.
+//
+// Returns:
+// - byte: The witness version.
+// - []byte: The witness program.
+// - error: An error if the address is not a valid SegWit address for the given human-readable part.
+func DecodeSegwitAddress(hrp, address string) (byte, []byte, error) {
+	decodedHrp, data, encoding, err := DecodeBech32(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if decodedHrp != hrp {
+		return 0, nil, fmt.Errorf("invalid human-readable part %q", decodedHrp)
+	}
+	if len(data) < 1 || data[0] > 16 {
+		return 0, nil, errors.New("invalid witness version")
+	}
+	if (data[0] == 0) != (encoding == Bech32) {
+		return 0, nil, fmt.Errorf("invalid checksum variant for witness version %d", data[0])
+	}
+
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return 0, nil, fmt.Errorf("invalid witness program length %d", len(program))
+	}
+	if data[0] == 0 && len(program) != 20 && len(program) != 32 {
+		return 0, nil, fmt.Errorf("invalid witness program length %d for version 0", len(program))
+	}
+	return data[0], program, nil
+}
+
+// bech32Polymod computes the Bech32 checksum polynomial over a sequence of 5-bit values.
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, value := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HrpExpand expands a human-readable part into the values used by the checksum computation.
+func bech32HrpExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// convertBits regroups a sequence of fromBits-wide values into toBits-wide values.
+// Without padding, leftover bits must be fewer than fromBits and all zero.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var result []byte
+	acc, bits, maxValue := uint32(0), uint(0), uint32(1)<<toBits-1
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data value %d", value)
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return result, nil
+}
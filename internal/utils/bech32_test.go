@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestDecodeBech32_ValidChecksums(t *testing.T) {
+	// Valid Bech32 strings from BIP-173.
+	tests := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"11qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqc8247j",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	}
+	for _, test := range tests {
+		if _, _, encoding, err := DecodeBech32(test); err != nil || encoding != Bech32 {
+			t.Errorf("%s: expected a Bech32 string, got (%v, %v)", test, encoding, err)
+		}
+	}
+}
+
+func TestDecodeBech32_Invalid(t *testing.T) {
+	// Invalid Bech32 strings from BIP-173.
+	tests := []string{
+		"\x201nwldj5",
+		"\x7f1axkwrx",
+		"\x801eym55h",
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"de1lg7wt\xff",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+	}
+	for _, test := range tests {
+		if _, _, _, err := DecodeBech32(test); err == nil {
+			t.Errorf("%q: expected an error", test)
+		}
+	}
+}
+
+func TestDecodeBech32_ValidBech32m(t *testing.T) {
+	// Valid Bech32m strings from BIP-350.
+	tests := []string{
+		"A1LQFN3A",
+		"a1lqfn3a",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+		"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		"?1v759aa",
+	}
+	for _, test := range tests {
+		if _, _, encoding, err := DecodeBech32(test); err != nil || encoding != Bech32m {
+			t.Errorf("%s: expected a Bech32m string, got (%v, %v)", test, encoding, err)
+		}
+	}
+}
+
+func TestDecodeSegwitAddress_Valid(t *testing.T) {
+	tests := []struct {
+		address string
+		version byte
+		program string
+	}{
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", 0, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", 0, "751e76e8199196d454941c45d1b3a323f1433bd6"},
+		{"BC1SW50QGDZ25J", 16, "751e"},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvaxxpcs", 2, "751e76e8199196d454941c45d1b3a323"},
+		{
+			"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", 1,
+			"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+	}
+	for _, test := range tests {
+		version, program, err := DecodeSegwitAddress("bc", test.address)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", test.address, err)
+			continue
+		}
+		if version != test.version || hex.EncodeToString(program) != test.program {
+			t.Errorf("%s: expected (%d, %s), got (%d, %x)", test.address, test.version, test.program, version, program)
+		}
+	}
+}
+
+func TestDecodeSegwitAddress_Invalid(t *testing.T) {
+	// Invalid SegWit addresses from BIP-173.
+	tests := []string{
+		"tc1qw508d6qejxtdg4y5r3zarvary0c5xw7kg3g4ty",
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+		"BC13W508D6QEJXTDG4Y5R3ZARVARY0C5XW7KN40WF2",
+		"bc1rw5uspcuh",
+		"bc10w508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kw5rljs90",
+		"BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P",
+		"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sL5k7",
+		"bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du",
+		"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3pjxtptv",
+		"bc1gmk9yu",
+		// Addresses with the wrong checksum variant for their witness version, from BIP-350.
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",
+		"BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL",
+		"bc1pw5dgrnzv",
+		"BC1SW50QA3JX3S",
+	}
+	for _, test := range tests {
+		if _, _, err := DecodeSegwitAddress("bc", test); err == nil {
+			t.Errorf("%s: expected an error", test)
+		}
+	}
+}
+
+func TestDecodeAddress_Types(t *testing.T) {
+	generator := CreatePublicHash160(big.NewInt(1))
+	tests := []struct {
+		address     string
+		addressType domain.AddressType
+	}{
+		{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", domain.P2PKH},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", domain.P2WPKH},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", domain.P2WPKH},
+	}
+	for _, test := range tests {
+		hash, addressType, err := DecodeAddress(test.address)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", test.address, err)
+			continue
+		}
+		if addressType != test.addressType || !bytes.Equal(hash, generator) {
+			t.Errorf("%s: expected (%v, %x), got (%v, %x)", test.address, test.addressType, generator, addressType, hash)
+		}
+	}
+}
+
+func TestDecodeAddress_RejectsNonP2WPKH(t *testing.T) {
+	// A version 0 witness script hash (P2WSH) and a version 1 program are not hash160 targets.
+	tests := []string{
+		"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3",
+		"bc1zw508d6qejxtdg4y5r3zarvaryvg6kdaj",
+	}
+	for _, test := range tests {
+		if _, _, err := DecodeAddress(test); err == nil {
+			t.Errorf("%s: expected an error", test)
+		}
+	}
+}
+
+func TestDecodeAddress_NestedSegwit(t *testing.T) {
+	// The P2SH-P2WPKH address of private key 1.
+	hash, addressType, err := DecodeAddress("3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := CreateNestedSegwitHash160(big.NewInt(1))
+	if addressType != domain.P2SHP2WPKH || !bytes.Equal(hash, expected) {
+		t.Errorf("expected (%v, %x), got (%v, %x)", domain.P2SHP2WPKH, expected, addressType, hash)
+	}
+}
@@ -15,14 +15,16 @@ import (
 //
 // This function takes a private key as a big.Int, converts it to a hexadecimal string,
 // and processes it to generate a WIF string. The process includes prefixing, suffixing,
-// computing checksums, and base58 encoding.
+// computing checksums, and base58 encoding. Keys used with uncompressed public keys are
+// encoded without the 0x01 compression suffix.
 //
 // Parameters:
 // - privKeyInt: A pointer to a big.Int representing the private key.
+// - compressed: Whether the key is used with its compressed public key.
 //
 // Returns:
 // - string: The WIF string representation of the private key.
-func GenerateWif(privKeyInt *big.Int) string {
+func GenerateWif(privKeyInt *big.Int, compressed bool) string {
 	privKeyHex := fmt.Sprintf("%064x", privKeyInt)
 
 	privKeyBytes, err := hex.DecodeString(privKeyHex)
@@ -31,7 +33,9 @@ func GenerateWif(privKeyInt *big.Int) string {
 	}
 
 	extendedKey := append([]byte{byte(0x80)}, privKeyBytes...)
-	extendedKey = append(extendedKey, byte(0x01))
+	if compressed {
+		extendedKey = append(extendedKey, byte(0x01))
+	}
 
 	firstSHA := sha256.Sum256(extendedKey)
 	secondSHA := sha256.Sum256(firstSHA[:])
@@ -56,9 +60,66 @@ func GenerateWif(privKeyInt *big.Int) string {
 // - []byte: The Hash160 of the public key.
 func CreatePublicHash160(privKeyInt *big.Int) []byte {
 	privKey := secp256k1.PrivKeyFromBytes(privKeyInt.Bytes())
-	compressedPubKey := privKey.PubKey().SerializeCompressed()
+	return PublicKeyHash160(privKey.PubKey())
+}
+
+// PublicKeyHash160 computes the Hash160 of a public key in compressed format.
+//
+// Parameters:
+// - pubKey: A pointer to the secp256k1.PublicKey to hash.
+//
+// Returns:
+// - []byte: The Hash160 of the compressed public key.
+func PublicKeyHash160(pubKey *secp256k1.PublicKey) []byte {
+	return hash160(pubKey.SerializeCompressed())
+}
+
+// CreateUncompressedPublicHash160 generates a Hash160 from a given private key using its uncompressed public key.
+//
+// Parameters:
+// - privKeyInt: A pointer to a big.Int representing the private key.
+//
+// Returns:
+// - []byte: The Hash160 of the uncompressed public key.
+func CreateUncompressedPublicHash160(privKeyInt *big.Int) []byte {
+	privKey := secp256k1.PrivKeyFromBytes(privKeyInt.Bytes())
+	return hash160(privKey.PubKey().SerializeUncompressed())
+}
+
+// CreatePublicKey derives the compressed public key of a given private key.
+//
+// Parameters:
+// - privKeyInt: A pointer to a big.Int representing the private key.
+//
+// Returns:
+// - []byte: The 33-byte compressed public key.
+func CreatePublicKey(privKeyInt *big.Int) []byte {
+	return CreatePublicPoint(privKeyInt).SerializeCompressed()
+}
+
+// CreatePublicPoint derives the public key of a given private key.
+//
+// Parameters:
+// - privKeyInt: A pointer to a big.Int representing the private key.
+//
+// Returns:
+// - *secp256k1.PublicKey: The public key.
+func CreatePublicPoint(privKeyInt *big.Int) *secp256k1.PublicKey {
+	return secp256k1.PrivKeyFromBytes(privKeyInt.Bytes()).PubKey()
+}
 
-	return hash160(compressedPubKey)
+// CreateNestedSegwitHash160 generates the P2SH-P2WPKH script hash from a given private key.
+//
+// This function wraps the Hash160 of the compressed public key in the redeem script 0x0014<hash160>
+// and computes the Hash160 of that script.
+//
+// Parameters:
+// - privKeyInt: A pointer to a big.Int representing the private key.
+//
+// Returns:
+// - []byte: The Hash160 of the P2WPKH redeem script.
+func CreateNestedSegwitHash160(privKeyInt *big.Int) []byte {
+	return hash160(append([]byte{0x00, 0x14}, CreatePublicHash160(privKeyInt)...))
 }
 
 // hash160 computes the Hash160 of a given byte slice.
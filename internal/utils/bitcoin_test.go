@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestGenerateWif_Compressed(t *testing.T) {
+	expected := "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"
+	if result := GenerateWif(big.NewInt(1), true); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestGenerateWif_Uncompressed(t *testing.T) {
+	expected := "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf"
+	if result := GenerateWif(big.NewInt(1), false); result != expected {
+		t.Errorf("expected %s, got %s", expected, result)
+	}
+}
+
+func TestCreateUncompressedPublicHash160(t *testing.T) {
+	expected := Decode("1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm")[1:21]
+	if result := CreateUncompressedPublicHash160(big.NewInt(1)); !bytes.Equal(result, expected) {
+		t.Errorf("expected %x, got %x", expected, result)
+	}
+}
+
+func TestHash160Uncompressed(t *testing.T) {
+	for _, key := range []int64{1, 2, 1 << 40} {
+		pubKey := secp256k1.PrivKeyFromBytes(big.NewInt(key).Bytes()).PubKey()
+		var x, y secp256k1.FieldVal
+		x.SetByteSlice(pubKey.X().Bytes())
+		y.SetByteSlice(pubKey.Y().Bytes())
+		var serialized [UncompressedPubKeySize]byte
+		var result [20]byte
+		SerializeUncompressed(&x, &y, &serialized)
+		Hash160Uncompressed(&serialized, &result)
+		if expected := CreateUncompressedPublicHash160(big.NewInt(key)); !bytes.Equal(result[:], expected) {
+			t.Errorf("key %d: expected %x, got %x", key, expected, result)
+		}
+	}
+}
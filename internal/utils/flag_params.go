@@ -2,6 +2,7 @@ package utils
 
 import (
 	"GoKeyHunt/internal/domain"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -31,18 +33,31 @@ func GetParameters(wallets domain.Wallets) *domain.Parameters {
 	var maxInt64 int64 = math.MaxInt64
 
 	// Variables to store flag values
-	var workerCount, targetWallet, updateInterval, batchCount int
-	var rng, verboseSummary, verboseProgress, verboseKeyFind bool
-	var usePreset string
-	var batchSize int64
+	var workerCount, targetWallet, updateInterval, batchCount, groupSize, checkpointMins int
+	var rng, permutation, skipInvalid, verboseSummary, verboseProgress, verboseKeyFind bool
+	var usePreset, addressModeName, searchModeName, gapModeName, publicKeyHex, seedText string
+	var batchSize, workUnitSize, memoryBudget, bitmapBlockSize, checkpointKeys int64
 
 	// Define flags
 	flag.IntVar(&workerCount, "t", 2, fmt.Sprintf("Worker thread count (available CPUs: %d).", runtime.NumCPU()))
+	flag.IntVar(&groupSize, "gs", 512, "Group size: number of consecutive keys that share a single modular inversion. Use 1 to derive each key on its own.")
 	flag.IntVar(&targetWallet, "w", 30, fmt.Sprintf("Target wallet (range: 0 to %d). Use 0 to search all wallets.", len(wallets.Addresses)))
 	flag.IntVar(&updateInterval, "u", 1, "Progress update interval in seconds.")
 	flag.Int64Var(&batchSize, "bs", -1, fmt.Sprintf("Batch size for execution (range: -1 to %d). If -1, will execute until the end of the wallet.", maxInt64))
+	flag.Int64Var(&workUnitSize, "ws", 1<<16, "Work unit size: number of consecutive keys handed to a worker at once.")
+	flag.IntVar(&checkpointMins, "cp", 0, "Checkpoint interval in minutes: save the checked part of a running batch to the progress file this often. Use 0 to save only when the batch ends.")
+	flag.Int64Var(&checkpointKeys, "cpk", 0, "Checkpoint interval in keys: save the checked part of a running batch to the progress file every time this many more keys are checked. Use 0 to disable.")
 	flag.IntVar(&batchCount, "bc", 1, fmt.Sprintf("Number of batches (range: 1 to %d). If -1, will execute until the end of the wallet.", math.MaxInt))
+	flag.StringVar(&addressModeName, "am", "compressed", "Address mode: public key encodings to check for every key (compressed, uncompressed or both).")
+	flag.StringVar(&searchModeName, "mode", "scan", "Search mode: scan every key of the range, or run Pollard's kangaroo or baby-step giant-step against a known public key (scan, kangaroo or bsgs).")
+	flag.StringVar(&publicKeyHex, "pk", "", "Target public key in hex for the kangaroo and bsgs modes. If empty, the target wallet must be a public key.")
+	flag.Int64Var(&memoryBudget, "mem", 256, "Memory budget in MiB for the baby-step table of the bsgs mode.")
+	flag.Int64Var(&bitmapBlockSize, "bm", 0, "Bitmap block size: if greater than 0, track the progress in a memory-mapped bitmap with one bit per block of this many keys instead of intervals. The work unit size and the batch size must be multiples of it. The interval progress is merged into the bitmap when it opens, and 'progress migrate' exports the bitmap back to intervals.")
 	flag.BoolVar(&rng, "rng", false, "If present, generate random start location.")
+	flag.StringVar(&seedText, "seed", "", "Seed of the random start locations or of a new block permutation, in decimal or 0x hex. If empty, a seed is generated and printed in the summary, so the run can be replayed. A replay repeats the random draws, but every batch still skips the keys the progress already covers, so the scanned batches differ once the progress has changed.")
+	flag.BoolVar(&permutation, "perm", false, "If present, visit the wallet range in blocks of -bs keys, in a seeded pseudorandom order that never repeats and resumes across runs.")
+	flag.StringVar(&gapModeName, "gap", "", "Largest-gap-first mode: place every batch inside the largest gap the progress does not cover yet, in its center or at a seeded random offset (center or random).")
+	flag.BoolVar(&skipInvalid, "si", false, "If present, skip invalid wallet addresses with a warning instead of refusing to start.")
 	flag.BoolVar(&verboseSummary, "vs", false, "Disable verbose output for summary.")
 	flag.BoolVar(&verboseProgress, "vp", false, "Disable verbose output for progress.")
 	flag.BoolVar(&verboseKeyFind, "vk", false, "Disable verbose output for key find.")
@@ -79,14 +94,114 @@ func GetParameters(wallets domain.Wallets) *domain.Parameters {
 		log.Fatalf("\nError: Batch count must be greater than 1.")
 	}
 
+	// Validate permutation
+	if permutation && (batchSize == -1 || rng) {
+		flag.Usage()
+		log.Fatalf("\nError: Permutation mode needs a batch size and cannot be combined with -rng.")
+	}
+
+	// Validate workUnitSize
+	if workUnitSize < 1 {
+		flag.Usage()
+		log.Fatalf("\nError: Work unit size must be greater than 0.")
+	}
+
+	// Validate checkpoints
+	if checkpointMins < 0 || checkpointKeys < 0 {
+		flag.Usage()
+		log.Fatalf("\nError: Checkpoint intervals must be 0 or greater than 0.")
+	}
+
+	// Validate groupSize
+	if groupSize < 1 {
+		flag.Usage()
+		log.Fatalf("\nError: Group size must be greater than 0.")
+	}
+
+	// Validate addressMode
+	addressMode, err := domain.ParseAddressMode(addressModeName)
+	if err != nil {
+		flag.Usage()
+		log.Fatalf("\nError: Address mode must be compressed, uncompressed or both.")
+	}
+
+	// Validate searchMode
+	searchMode, err := domain.ParseSearchMode(searchModeName)
+	if err != nil {
+		flag.Usage()
+		log.Fatalf("\nError: Search mode must be scan, kangaroo or bsgs.")
+	}
+
+	// Validate gapMode
+	gapMode, err := domain.ParseGapMode(gapModeName)
+	if err != nil || (gapMode != domain.NoGap && (rng || permutation)) {
+		flag.Usage()
+		log.Fatalf("\nError: Gap mode must be center or random, and cannot be combined with -rng or -perm.")
+	}
+	seeded := rng || permutation || gapMode == domain.RandomGap
+
+	// Validate memoryBudget
+	if memoryBudget < 1 || memoryBudget > math.MaxInt64>>20 {
+		flag.Usage()
+		log.Fatalf("\nError: Memory budget must be greater than 0.")
+	}
+
+	// Validate bitmapBlockSize
+	if bitmapBlockSize < 0 {
+		flag.Usage()
+		log.Fatalf("\nError: Bitmap block size must be 0 or greater than 0.")
+	}
+
+	// Validate the sizes used with a bitmap, whose blocks are only marked when a work unit covers them whole
+	if bitmapBlockSize > 0 && (workUnitSize%bitmapBlockSize != 0 || (batchSize != -1 && batchSize%bitmapBlockSize != 0)) {
+		flag.Usage()
+		log.Fatalf("\nError: With a bitmap, the work unit size and a batch size other than -1 must be multiples of the bitmap block size.")
+	}
+
+	// Validate seed
+	var seed uint64
+	if seedText != empty {
+		if seed, err = strconv.ParseUint(seedText, 0, 64); err != nil || !seeded {
+			flag.Usage()
+			log.Fatalf("\nError: Seed must be a 64-bit number in decimal or 0x hex, and is only used with -rng, -perm or -gap random.")
+		}
+	} else if seeded {
+		if seed, err = GenerateSeed(); err != nil {
+			log.Fatalf("\nError: Could not generate a seed: %v", err)
+		}
+	}
+
+	// Validate publicKey
+	var publicKey []byte
+	if publicKeyHex != empty {
+		if _, addressType, err := DecodeAddress(publicKeyHex); err != nil || addressType != domain.PubKey {
+			flag.Usage()
+			log.Fatalf("\nError: Public key must be a valid compressed or uncompressed key in hex.")
+		}
+		publicKey, _ = hex.DecodeString(publicKeyHex)
+	}
+
 	// Return parameters
 	return &domain.Parameters{
 		WorkerCount:     workerCount,
 		TargetWallet:    targetWallet,
 		UpdateInterval:  updateInterval,
 		BatchSize:       batchSize,
+		WorkUnitSize:    workUnitSize,
+		CheckpointKeys:  checkpointKeys,
+		MemoryBudget:    memoryBudget << 20,
+		Seed:            seed,
+		BitmapBlockSize: bitmapBlockSize,
+		AddressMode:     addressMode,
+		SearchMode:      searchMode,
+		GapMode:         gapMode,
+		PublicKey:       publicKey,
 		BatchCount:      batchCount,
+		GroupSize:       groupSize,
+		CheckpointMins:  checkpointMins,
 		Rng:             rng,
+		Permutation:     permutation,
+		SkipInvalid:     skipInvalid,
 		VerboseSummary:  !verboseSummary,
 		VerboseProgress: !verboseProgress,
 		VerboseKeyFind:  !verboseKeyFind,
@@ -0,0 +1,439 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// CompressedPubKeySize is the size in bytes of a compressed public key.
+const CompressedPubKeySize = 33
+
+// UncompressedPubKeySize is the size in bytes of an uncompressed public key.
+const UncompressedPubKeySize = 65
+
+// P2WPKHScriptSize is the size in bytes of a P2WPKH witness script: OP_0, a 20-byte push and the public key hash.
+const P2WPKHScriptSize = 22
+
+// Hash160Compressed computes the Hash160 of a 33-byte compressed public key into out.
+//
+// The SHA-256 step uses the one-shot sha256.Sum256, which is hardware accelerated where available and does not
+// allocate. The 32-byte digest always fits in a single RIPEMD-160 block, so the RIPEMD-160 step runs a single
+// compression with fixed padding directly on the digest, without any heap allocation.
+//
+// Parameters:
+// - pubKey: A pointer to the 33-byte compressed public key.
+// - out: A pointer to the 20-byte array that receives the Hash160.
+func Hash160Compressed(pubKey *[CompressedPubKeySize]byte, out *[20]byte) {
+	digest := sha256.Sum256(pubKey[:])
+	ripemd160Digest(&digest, out)
+}
+
+// Hash160Uncompressed computes the Hash160 of a 65-byte uncompressed public key into out, without heap allocations.
+//
+// Parameters:
+// - pubKey: A pointer to the 65-byte uncompressed public key.
+// - out: A pointer to the 20-byte array that receives the Hash160.
+func Hash160Uncompressed(pubKey *[UncompressedPubKeySize]byte, out *[20]byte) {
+	digest := sha256.Sum256(pubKey[:])
+	ripemd160Digest(&digest, out)
+}
+
+// Hash160P2WPKHScript computes the Hash160 of the P2WPKH redeem script 0x0014<pubKeyHash> into out, without heap
+// allocations. This is the script hash a nested SegWit (P2SH-P2WPKH) address pays to. out may alias pubKeyHash.
+//
+// Parameters:
+// - pubKeyHash: A pointer to the Hash160 of the compressed public key.
+// - out: A pointer to the 20-byte array that receives the script hash.
+func Hash160P2WPKHScript(pubKeyHash *[20]byte, out *[20]byte) {
+	var script [P2WPKHScriptSize]byte
+	script[0], script[1] = 0x00, 0x14
+	copy(script[2:], pubKeyHash[:])
+	digest := sha256.Sum256(script[:])
+	ripemd160Digest(&digest, out)
+}
+
+// SerializeCompressed writes the compressed encoding of the affine point (x, y) into out.
+// Both coordinates must be normalized.
+//
+// Parameters:
+// - x: A pointer to the normalized x coordinate.
+// - y: A pointer to the normalized y coordinate.
+// - out: A pointer to the 33-byte array that receives the compressed public key.
+func SerializeCompressed(x, y *secp256k1.FieldVal, out *[CompressedPubKeySize]byte) {
+	out[0] = 0x02
+	if y.IsOdd() {
+		out[0] = 0x03
+	}
+	x.PutBytesUnchecked(out[1:])
+}
+
+// SerializeUncompressed writes the uncompressed encoding of the affine point (x, y) into out.
+// Both coordinates must be normalized.
+//
+// Parameters:
+// - x: A pointer to the normalized x coordinate.
+// - y: A pointer to the normalized y coordinate.
+// - out: A pointer to the 65-byte array that receives the uncompressed public key.
+func SerializeUncompressed(x, y *secp256k1.FieldVal, out *[UncompressedPubKeySize]byte) {
+	out[0] = 0x04
+	x.PutBytesUnchecked(out[1:33])
+	y.PutBytesUnchecked(out[33:])
+}
+
+// ripemd160Digest computes the RIPEMD-160 of a 32-byte digest.
+//
+// The message is padded to a single 64-byte block and the compression function is fully unrolled, so every
+// message word index and rotation amount is a constant. Each step rotates the roles of the five working
+// variables instead of moving their values.
+func ripemd160Digest(digest *[32]byte, out *[20]byte) {
+	var x [16]uint32
+	for i := 0; i < 8; i++ {
+		x[i] = binary.LittleEndian.Uint32(digest[i*4:])
+	}
+	x[8] = 0x80
+	x[14] = 32 * 8
+
+	h0, h1, h2, h3, h4 := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476), uint32(0xc3d2e1f0)
+	a, b, c, d, e := h0, h1, h2, h3, h4
+	aa, bb, cc, dd, ee := h0, h1, h2, h3, h4
+
+	// Left line.
+	a = bits.RotateLeft32(a+(b^c^d)+x[0], 11) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+(a^b^c)+x[1], 14) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+(e^a^b)+x[2], 15) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+(d^e^a)+x[3], 12) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^d^e)+x[4], 5) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+(b^c^d)+x[5], 8) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+(a^b^c)+x[6], 7) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+(e^a^b)+x[7], 9) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+(d^e^a)+x[8], 11) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^d^e)+x[9], 13) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+(b^c^d)+x[10], 14) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+(a^b^c)+x[11], 15) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+(e^a^b)+x[12], 6) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+(d^e^a)+x[13], 7) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^d^e)+x[14], 9) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+(b^c^d)+x[15], 8) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&b)|(^a&c))+x[7]+0x5a827999, 7) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e&a)|(^e&b))+x[4]+0x5a827999, 6) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&e)|(^d&a))+x[13]+0x5a827999, 8) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c&d)|(^c&e))+x[1]+0x5a827999, 13) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b&c)|(^b&d))+x[10]+0x5a827999, 11) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&b)|(^a&c))+x[6]+0x5a827999, 9) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e&a)|(^e&b))+x[15]+0x5a827999, 7) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&e)|(^d&a))+x[3]+0x5a827999, 15) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c&d)|(^c&e))+x[12]+0x5a827999, 7) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b&c)|(^b&d))+x[0]+0x5a827999, 12) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&b)|(^a&c))+x[9]+0x5a827999, 15) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e&a)|(^e&b))+x[5]+0x5a827999, 9) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&e)|(^d&a))+x[2]+0x5a827999, 11) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c&d)|(^c&e))+x[14]+0x5a827999, 7) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b&c)|(^b&d))+x[11]+0x5a827999, 13) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&b)|(^a&c))+x[8]+0x5a827999, 12) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e|^a)^b)+x[3]+0x6ed9eba1, 11) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d|^e)^a)+x[10]+0x6ed9eba1, 13) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c|^d)^e)+x[14]+0x6ed9eba1, 6) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b|^c)^d)+x[4]+0x6ed9eba1, 7) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a|^b)^c)+x[9]+0x6ed9eba1, 14) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e|^a)^b)+x[15]+0x6ed9eba1, 9) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d|^e)^a)+x[8]+0x6ed9eba1, 13) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c|^d)^e)+x[1]+0x6ed9eba1, 15) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b|^c)^d)+x[2]+0x6ed9eba1, 14) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a|^b)^c)+x[7]+0x6ed9eba1, 8) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e|^a)^b)+x[0]+0x6ed9eba1, 13) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d|^e)^a)+x[6]+0x6ed9eba1, 6) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c|^d)^e)+x[13]+0x6ed9eba1, 5) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b|^c)^d)+x[11]+0x6ed9eba1, 12) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a|^b)^c)+x[5]+0x6ed9eba1, 7) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e|^a)^b)+x[12]+0x6ed9eba1, 5) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&a)|(e&^a))+x[1]+0x8f1bbcdc, 11) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c&e)|(d&^e))+x[9]+0x8f1bbcdc, 12) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b&d)|(c&^d))+x[11]+0x8f1bbcdc, 14) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&c)|(b&^c))+x[10]+0x8f1bbcdc, 15) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e&b)|(a&^b))+x[0]+0x8f1bbcdc, 14) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&a)|(e&^a))+x[8]+0x8f1bbcdc, 15) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c&e)|(d&^e))+x[12]+0x8f1bbcdc, 9) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b&d)|(c&^d))+x[4]+0x8f1bbcdc, 8) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&c)|(b&^c))+x[13]+0x8f1bbcdc, 9) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e&b)|(a&^b))+x[3]+0x8f1bbcdc, 14) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&a)|(e&^a))+x[7]+0x8f1bbcdc, 5) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+((c&e)|(d&^e))+x[15]+0x8f1bbcdc, 6) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+((b&d)|(c&^d))+x[14]+0x8f1bbcdc, 8) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+((a&c)|(b&^c))+x[5]+0x8f1bbcdc, 6) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+((e&b)|(a&^b))+x[6]+0x8f1bbcdc, 5) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+((d&a)|(e&^a))+x[2]+0x8f1bbcdc, 12) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^(d|^e))+x[4]+0xa953fd4e, 9) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+(b^(c|^d))+x[0]+0xa953fd4e, 15) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+(a^(b|^c))+x[5]+0xa953fd4e, 5) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+(e^(a|^b))+x[9]+0xa953fd4e, 11) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+(d^(e|^a))+x[7]+0xa953fd4e, 6) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^(d|^e))+x[12]+0xa953fd4e, 8) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+(b^(c|^d))+x[2]+0xa953fd4e, 13) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+(a^(b|^c))+x[10]+0xa953fd4e, 12) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+(e^(a|^b))+x[14]+0xa953fd4e, 5) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+(d^(e|^a))+x[1]+0xa953fd4e, 12) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^(d|^e))+x[3]+0xa953fd4e, 13) + a
+	d = bits.RotateLeft32(d, 10)
+	a = bits.RotateLeft32(a+(b^(c|^d))+x[8]+0xa953fd4e, 14) + e
+	c = bits.RotateLeft32(c, 10)
+	e = bits.RotateLeft32(e+(a^(b|^c))+x[11]+0xa953fd4e, 11) + d
+	b = bits.RotateLeft32(b, 10)
+	d = bits.RotateLeft32(d+(e^(a|^b))+x[6]+0xa953fd4e, 8) + c
+	a = bits.RotateLeft32(a, 10)
+	c = bits.RotateLeft32(c+(d^(e|^a))+x[15]+0xa953fd4e, 5) + b
+	e = bits.RotateLeft32(e, 10)
+	b = bits.RotateLeft32(b+(c^(d|^e))+x[13]+0xa953fd4e, 6) + a
+	d = bits.RotateLeft32(d, 10)
+
+	// Right line.
+	aa = bits.RotateLeft32(aa+(bb^(cc|^dd))+x[5]+0x50a28be6, 8) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+(aa^(bb|^cc))+x[14]+0x50a28be6, 9) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+(ee^(aa|^bb))+x[7]+0x50a28be6, 9) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+(dd^(ee|^aa))+x[0]+0x50a28be6, 11) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^(dd|^ee))+x[9]+0x50a28be6, 13) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+(bb^(cc|^dd))+x[2]+0x50a28be6, 15) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+(aa^(bb|^cc))+x[11]+0x50a28be6, 15) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+(ee^(aa|^bb))+x[4]+0x50a28be6, 5) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+(dd^(ee|^aa))+x[13]+0x50a28be6, 7) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^(dd|^ee))+x[6]+0x50a28be6, 7) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+(bb^(cc|^dd))+x[15]+0x50a28be6, 8) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+(aa^(bb|^cc))+x[8]+0x50a28be6, 11) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+(ee^(aa|^bb))+x[1]+0x50a28be6, 14) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+(dd^(ee|^aa))+x[10]+0x50a28be6, 14) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^(dd|^ee))+x[3]+0x50a28be6, 12) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+(bb^(cc|^dd))+x[12]+0x50a28be6, 6) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&cc)|(bb&^cc))+x[6]+0x5c4dd124, 9) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee&bb)|(aa&^bb))+x[11]+0x5c4dd124, 13) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&aa)|(ee&^aa))+x[3]+0x5c4dd124, 15) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc&ee)|(dd&^ee))+x[7]+0x5c4dd124, 7) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb&dd)|(cc&^dd))+x[0]+0x5c4dd124, 12) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&cc)|(bb&^cc))+x[13]+0x5c4dd124, 8) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee&bb)|(aa&^bb))+x[5]+0x5c4dd124, 9) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&aa)|(ee&^aa))+x[10]+0x5c4dd124, 11) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc&ee)|(dd&^ee))+x[14]+0x5c4dd124, 7) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb&dd)|(cc&^dd))+x[15]+0x5c4dd124, 7) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&cc)|(bb&^cc))+x[8]+0x5c4dd124, 12) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee&bb)|(aa&^bb))+x[12]+0x5c4dd124, 7) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&aa)|(ee&^aa))+x[4]+0x5c4dd124, 6) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc&ee)|(dd&^ee))+x[9]+0x5c4dd124, 15) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb&dd)|(cc&^dd))+x[1]+0x5c4dd124, 13) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&cc)|(bb&^cc))+x[2]+0x5c4dd124, 11) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee|^aa)^bb)+x[15]+0x6d703ef3, 9) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd|^ee)^aa)+x[5]+0x6d703ef3, 7) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc|^dd)^ee)+x[1]+0x6d703ef3, 15) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb|^cc)^dd)+x[3]+0x6d703ef3, 11) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa|^bb)^cc)+x[7]+0x6d703ef3, 8) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee|^aa)^bb)+x[14]+0x6d703ef3, 6) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd|^ee)^aa)+x[6]+0x6d703ef3, 6) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc|^dd)^ee)+x[9]+0x6d703ef3, 14) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb|^cc)^dd)+x[11]+0x6d703ef3, 12) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa|^bb)^cc)+x[8]+0x6d703ef3, 13) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee|^aa)^bb)+x[12]+0x6d703ef3, 5) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd|^ee)^aa)+x[2]+0x6d703ef3, 14) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc|^dd)^ee)+x[10]+0x6d703ef3, 13) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb|^cc)^dd)+x[0]+0x6d703ef3, 13) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa|^bb)^cc)+x[4]+0x6d703ef3, 7) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee|^aa)^bb)+x[13]+0x6d703ef3, 5) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&ee)|(^dd&aa))+x[8]+0x7a6d76e9, 15) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc&dd)|(^cc&ee))+x[6]+0x7a6d76e9, 5) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb&cc)|(^bb&dd))+x[4]+0x7a6d76e9, 8) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&bb)|(^aa&cc))+x[1]+0x7a6d76e9, 11) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee&aa)|(^ee&bb))+x[3]+0x7a6d76e9, 14) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&ee)|(^dd&aa))+x[11]+0x7a6d76e9, 14) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc&dd)|(^cc&ee))+x[15]+0x7a6d76e9, 6) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb&cc)|(^bb&dd))+x[0]+0x7a6d76e9, 14) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&bb)|(^aa&cc))+x[5]+0x7a6d76e9, 6) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee&aa)|(^ee&bb))+x[12]+0x7a6d76e9, 9) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&ee)|(^dd&aa))+x[2]+0x7a6d76e9, 12) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+((cc&dd)|(^cc&ee))+x[13]+0x7a6d76e9, 9) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+((bb&cc)|(^bb&dd))+x[9]+0x7a6d76e9, 12) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+((aa&bb)|(^aa&cc))+x[7]+0x7a6d76e9, 5) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+((ee&aa)|(^ee&bb))+x[10]+0x7a6d76e9, 15) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+((dd&ee)|(^dd&aa))+x[14]+0x7a6d76e9, 8) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^dd^ee)+x[12], 8) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+(bb^cc^dd)+x[15], 5) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+(aa^bb^cc)+x[10], 12) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+(ee^aa^bb)+x[4], 9) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+(dd^ee^aa)+x[1], 12) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^dd^ee)+x[5], 5) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+(bb^cc^dd)+x[8], 14) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+(aa^bb^cc)+x[7], 6) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+(ee^aa^bb)+x[6], 8) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+(dd^ee^aa)+x[2], 13) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^dd^ee)+x[13], 6) + aa
+	dd = bits.RotateLeft32(dd, 10)
+	aa = bits.RotateLeft32(aa+(bb^cc^dd)+x[14], 5) + ee
+	cc = bits.RotateLeft32(cc, 10)
+	ee = bits.RotateLeft32(ee+(aa^bb^cc)+x[0], 15) + dd
+	bb = bits.RotateLeft32(bb, 10)
+	dd = bits.RotateLeft32(dd+(ee^aa^bb)+x[3], 13) + cc
+	aa = bits.RotateLeft32(aa, 10)
+	cc = bits.RotateLeft32(cc+(dd^ee^aa)+x[9], 11) + bb
+	ee = bits.RotateLeft32(ee, 10)
+	bb = bits.RotateLeft32(bb+(cc^dd^ee)+x[11], 11) + aa
+	dd = bits.RotateLeft32(dd, 10)
+
+	t := h1 + c + dd
+	h1 = h2 + d + ee
+	h2 = h3 + e + aa
+	h3 = h4 + a + bb
+	h4 = h0 + b + cc
+	h0 = t
+
+	binary.LittleEndian.PutUint32(out[0:], h0)
+	binary.LittleEndian.PutUint32(out[4:], h1)
+	binary.LittleEndian.PutUint32(out[8:], h2)
+	binary.LittleEndian.PutUint32(out[12:], h3)
+	binary.LittleEndian.PutUint32(out[16:], h4)
+}
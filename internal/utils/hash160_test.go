@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// compressedPubKey returns the compressed public key of a private key as a fixed-size array.
+func compressedPubKey(privKeyInt *big.Int) *[CompressedPubKeySize]byte {
+	var pubKey [CompressedPubKeySize]byte
+	copy(pubKey[:], secp256k1.PrivKeyFromBytes(privKeyInt.Bytes()).PubKey().SerializeCompressed())
+	return &pubKey
+}
+
+func TestHash160Compressed_Vectors(t *testing.T) {
+	vectors := []struct {
+		privKey int64
+		address string
+	}{
+		{1, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{3, "1CUNEBjYrCn2y1SdiUMohaKUi4wpP326Lb"},
+		{7, "19ZewH8Kk1PDbSNdJ97FP4EiCjTRaZMZQA"},
+		{0xd2c55, "1HsMJxNiV7TLxmoF6uJNkydxPFDog4NQum"},
+	}
+	for _, vector := range vectors {
+		var result [20]byte
+		Hash160Compressed(compressedPubKey(big.NewInt(vector.privKey)), &result)
+		expected := Decode(vector.address)[1:21]
+		if !bytes.Equal(result[:], expected) {
+			t.Errorf("key %d: expected %x, got %x", vector.privKey, expected, result)
+		}
+	}
+}
+
+func TestHash160Compressed_GeneratorVector(t *testing.T) {
+	pubKey, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	var input [CompressedPubKeySize]byte
+	copy(input[:], pubKey)
+	var result [20]byte
+	Hash160Compressed(&input, &result)
+	if hex.EncodeToString(result[:]) != "751e76e8199196d454941c45d1b3a323f1433bd6" {
+		t.Errorf("expected 751e76e8199196d454941c45d1b3a323f1433bd6, got %x", result)
+	}
+}
+
+func TestSerializeCompressed(t *testing.T) {
+	for _, key := range []int64{1, 2, 3, 1 << 40} {
+		pubKey := secp256k1.PrivKeyFromBytes(big.NewInt(key).Bytes()).PubKey()
+		var x, y secp256k1.FieldVal
+		x.SetByteSlice(pubKey.X().Bytes())
+		y.SetByteSlice(pubKey.Y().Bytes())
+		var result [CompressedPubKeySize]byte
+		SerializeCompressed(&x, &y, &result)
+		if !bytes.Equal(result[:], pubKey.SerializeCompressed()) {
+			t.Errorf("key %d: expected %x, got %x", key, pubKey.SerializeCompressed(), result)
+		}
+	}
+}
+
+func TestHash160Compressed_NoAllocations(t *testing.T) {
+	pubKey := compressedPubKey(big.NewInt(12345))
+	var result [20]byte
+	allocs := testing.AllocsPerRun(100, func() {
+		Hash160Compressed(pubKey, &result)
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocations, got %v", allocs)
+	}
+}
+
+func FuzzHash160Compressed(f *testing.F) {
+	f.Add(compressedPubKey(big.NewInt(1))[:])
+	f.Add(make([]byte, CompressedPubKeySize))
+	f.Add(bytes.Repeat([]byte{0xff}, CompressedPubKeySize))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var input [CompressedPubKeySize]byte
+		copy(input[:], data)
+		var result [20]byte
+		Hash160Compressed(&input, &result)
+		if expected := hash160(input[:]); !bytes.Equal(result[:], expected) {
+			t.Errorf("input %x: expected %x, got %x", input, expected, result)
+		}
+	})
+}
+
+func BenchmarkHash160(b *testing.B) {
+	pubKey := compressedPubKey(big.NewInt(12345))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hash160(pubKey[:])
+	}
+}
+
+func BenchmarkHash160Compressed(b *testing.B) {
+	pubKey := compressedPubKey(big.NewInt(12345))
+	var result [20]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Hash160Compressed(pubKey, &result)
+	}
+}
+
+func TestHash160P2WPKHScript(t *testing.T) {
+	for i := int64(1); i < 64; i++ {
+		key := big.NewInt(i)
+		var hash [20]byte
+		copy(hash[:], CreatePublicHash160(key))
+		Hash160P2WPKHScript(&hash, &hash)
+		if expected := CreateNestedSegwitHash160(key); !bytes.Equal(hash[:], expected) {
+			t.Errorf("key %d: expected %x, got %x", i, expected, hash)
+		}
+	}
+}
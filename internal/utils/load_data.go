@@ -13,12 +13,14 @@ import (
 //
 // This function retrieves the root directory of the application, and then loads
 // the ranges and wallets data from JSON files located in the "data" directory.
-// It logs a fatal error if either file fails to load.
+// It logs a fatal error if either file fails to load. Addresses that cannot be decoded are
+// returned separately, so the caller can decide whether to refuse to start or skip them.
 //
 // Returns:
 // - *domain.Ranges: A pointer to a Ranges structure containing the loaded ranges data.
 // - *domain.Wallets: A pointer to a Wallets structure containing the loaded wallets data.
-func LoadData() (*domain.Ranges, *domain.Wallets) {
+// - []*AddressError: The wallet addresses that could not be decoded.
+func LoadData() (*domain.Ranges, *domain.Wallets, []*AddressError) {
 	rootDir := GetRootDir()
 
 	ranges, err := LoadRanges(filepath.Join(rootDir, "data", "ranges.json"))
@@ -26,12 +28,12 @@ func LoadData() (*domain.Ranges, *domain.Wallets) {
 		log.Fatalf("Failed to load ranges: %v", err)
 	}
 
-	wallets, err := LoadWallets(filepath.Join(rootDir, "data", "wallets.json"))
+	wallets, invalid, err := LoadWallets(filepath.Join(rootDir, "data", "wallets.json"))
 	if err != nil {
 		log.Fatalf("Failed to load wallets: %v", err)
 	}
 
-	return ranges, wallets
+	return ranges, wallets, invalid
 }
 
 // LoadRanges loads ranges data from a specified JSON file.
@@ -68,25 +70,28 @@ func LoadRanges(filename string) (*domain.Ranges, error) {
 // LoadWallets loads wallets data from a specified JSON file.
 //
 // This function reads the JSON file, unmarshals its content into a temporary structure,
-// decodes the Base58 addresses, and returns a pointer to the Wallets structure or an error
-// if the operation fails.
+// decodes and validates the Base58Check, Bech32 and public key addresses, indexes them for constant-time
+// lookup, and returns a pointer to the Wallets structure or an error if the operation fails.
+// An address that cannot be decoded is reported as an AddressError and kept as an Invalid placeholder,
+// so the wallet numbers of the following addresses stay aligned with the file.
 //
 // Parameters:
 // - filename: The path to the JSON file containing the wallets data.
 //
 // Returns:
 // - *domain.Wallets: A pointer to a Wallets structure containing the loaded wallets data.
+// - []*AddressError: The addresses that could not be decoded, in file order.
 // - error: An error if there is an issue reading or unmarshalling the file.
-func LoadWallets(filename string) (*domain.Wallets, error) {
+func LoadWallets(filename string) (*domain.Wallets, []*AddressError, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer file.Close()
 
 	bytes, err := io.ReadAll(file)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	type WalletsTemp struct {
@@ -95,13 +100,45 @@ func LoadWallets(filename string) (*domain.Wallets, error) {
 
 	var walletsTemp WalletsTemp
 	if err := json.Unmarshal(bytes, &walletsTemp); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var wallets domain.Wallets
-	for _, address := range walletsTemp.Addresses {
-		wallets.Addresses = append(wallets.Addresses, Decode(address)[1:21])
+	var addresses [][]byte
+	var entries []domain.WalletEntry
+	var invalid []*AddressError
+	for i, address := range walletsTemp.Addresses {
+		hash, addressType, err := DecodeAddress(address)
+		if err != nil {
+			invalid = append(invalid, &AddressError{File: filename, Index: i + 1, Address: address, Reason: err})
+			hash, addressType = nil, domain.Invalid
+		}
+		addresses = append(addresses, hash)
+		entries = append(entries, domain.WalletEntry{Address: address, Type: addressType})
 	}
 
-	return &wallets, nil
+	wallets := domain.NewWallets(addresses)
+	wallets.Entries = entries
+	return wallets, invalid, nil
+}
+
+// HandleInvalidWallets refuses to start when some wallet addresses could not be decoded, unless
+// params.SkipInvalid is set, in which case a warning is logged for each skipped address.
+//
+// Parameters:
+// - invalid: The addresses that could not be decoded.
+// - params: A domain.Parameters instance containing configuration parameters, including SkipInvalid.
+func HandleInvalidWallets(invalid []*AddressError, params domain.Parameters) {
+	if len(invalid) == 0 {
+		return
+	}
+	for _, err := range invalid {
+		if params.SkipInvalid {
+			log.Printf("Warning: skipping invalid address %v", err)
+		} else {
+			log.Printf("Invalid address %v", err)
+		}
+	}
+	if !params.SkipInvalid {
+		log.Fatalf("\nError: %d invalid wallet addresses. Fix wallets.json or use -si to skip them.", len(invalid))
+	}
 }
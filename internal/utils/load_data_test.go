@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWallets_InvalidAddresses(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "wallets.json")
+	content := `{"wallets": ["1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"]}`
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wallets, invalid, err := LoadWallets(filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(invalid) != 1 {
+		t.Fatalf("expected 1 invalid address, got %d", len(invalid))
+	}
+	if invalid[0].File != filename || invalid[0].Index != 1 || invalid[0].Reason.Error() != "invalid checksum" {
+		t.Errorf("expected wallet 1 of %s with an invalid checksum, got %v", filename, invalid[0])
+	}
+	var addressError *AddressError
+	if !errors.As(error(invalid[0]), &addressError) {
+		t.Errorf("expected an *AddressError")
+	}
+
+	// The invalid address keeps its position, so the valid one is still wallet 2.
+	if wallets.Entry(0).Type != domain.Invalid {
+		t.Errorf("expected %v, got %v", domain.Invalid, wallets.Entry(0).Type)
+	}
+	if result := wallets.Find(CreatePublicHash160(big.NewInt(1))); result != 1 {
+		t.Errorf("expected 1, got %d", result)
+	}
+}
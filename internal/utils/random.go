@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// GenerateSeed generates a random seed for the seeded random mode.
+//
+// Returns:
+// - uint64: A random seed.
+// - error: An error if the system random source fails.
+func GenerateSeed() (uint64, error) {
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(seed[:]), nil
+}
+
+// SeedBytes returns the big-endian encoding of a seed, as used to key other pseudorandom structures.
+//
+// Parameters:
+// - seed: The seed to encode.
+//
+// Returns:
+// - []byte: The 8-byte encoding of the seed.
+func SeedBytes(seed uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seed)
+}
+
+// SeededRandomNumber returns the pseudorandom big.Int between the specified minimum and maximum values (inclusive)
+// drawn for a counter under a seed.
+//
+// The value only depends on the seed, the counter and the bounds: bytes are drawn from SHA-256 over the seed, the
+// counter and an attempt number, and values outside the range are rejected, so the same inputs always give the
+// same value on every platform and Go version.
+//
+// Parameters:
+// - seed: The seed of the run.
+// - counter: The position of the value in the sequence, such as the batch counter.
+// - minimum: A pointer to a big.Int representing the minimum value.
+// - maximum: A pointer to a big.Int representing the maximum value.
+//
+// Returns:
+// - *big.Int: A pseudorandom big.Int within the specified range.
+// - error: An error if the minimum value is greater than the maximum value.
+func SeededRandomNumber(seed, counter uint64, minimum, maximum *big.Int) (*big.Int, error) {
+	if minimum.Cmp(maximum) > 0 {
+		return nil, fmt.Errorf("the minimum value cannot be greater than the maximum value")
+	}
+
+	interval := new(big.Int).Sub(maximum, minimum)
+	interval.Add(interval, big.NewInt(1))
+	bits := interval.BitLen()
+	length := (bits + 7) / 8
+
+	var input [28]byte
+	binary.BigEndian.PutUint64(input[0:], seed)
+	binary.BigEndian.PutUint64(input[8:], counter)
+	randNum := new(big.Int)
+	for attempt := uint64(0); ; attempt++ {
+		binary.BigEndian.PutUint64(input[16:], attempt)
+		stream := make([]byte, 0, length+sha256.Size)
+		for block := uint32(0); len(stream) < length; block++ {
+			binary.BigEndian.PutUint32(input[24:], block)
+			digest := sha256.Sum256(input[:])
+			stream = append(stream, digest[:]...)
+		}
+		stream = stream[:length]
+		if extra := length*8 - bits; extra > 0 {
+			stream[0] &= 0xff >> extra
+		}
+		if randNum.SetBytes(stream).Cmp(interval) < 0 {
+			return randNum.Add(randNum, minimum), nil
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSeededRandomNumber_Reproducible(t *testing.T) {
+	minimum, maximum := big.NewInt(1000), big.NewInt(1999)
+	for counter := uint64(0); counter < 100; counter++ {
+		first, err := SeededRandomNumber(42, counter, minimum, maximum)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		second, _ := SeededRandomNumber(42, counter, minimum, maximum)
+		if first.Cmp(second) != 0 {
+			t.Errorf("expected %v, got %v", first, second)
+		}
+		if first.Cmp(minimum) < 0 || first.Cmp(maximum) > 0 {
+			t.Errorf("expected a value in [%v, %v], got %v", minimum, maximum, first)
+		}
+	}
+}
+
+func TestSeededRandomNumber_KnownValue(t *testing.T) {
+	// The sequence must not depend on the Go version, or old seeds could no longer be replayed.
+	minimum, _ := new(big.Int).SetString("20000000000000000", 16)
+	maximum, _ := new(big.Int).SetString("3ffffffffffffffff", 16)
+	expected := "2fda4e8d921ca777a"
+	if result, _ := SeededRandomNumber(1, 1, minimum, maximum); result.Text(16) != expected {
+		t.Errorf("expected %s, got %s", expected, result.Text(16))
+	}
+}
+
+func TestSeededRandomNumber_SeedsDiffer(t *testing.T) {
+	minimum, maximum := big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), 64)
+	first, _ := SeededRandomNumber(1, 1, minimum, maximum)
+	second, _ := SeededRandomNumber(2, 1, minimum, maximum)
+	if first.Cmp(second) == 0 {
+		t.Errorf("expected different values for different seeds, got %v twice", first)
+	}
+}
+
+func TestSeededRandomNumber_SingleValue(t *testing.T) {
+	value := big.NewInt(7)
+	result, err := SeededRandomNumber(3, 9, value, value)
+	if err != nil || result.Cmp(value) != 0 {
+		t.Errorf("expected %v, got %v (%v)", value, result, err)
+	}
+	if _, err := SeededRandomNumber(3, 9, big.NewInt(2), big.NewInt(1)); err == nil {
+		t.Errorf("expected an error for an empty range")
+	}
+}
+
+func TestGetStart_SeedReplaysBatches(t *testing.T) {
+	start, end := big.NewInt(1<<20), big.NewInt(1<<21-1)
+	params := domain.Parameters{Rng: true, Seed: 0xdeadbeef, BatchSize: 1000}
+	var sequence []*big.Int
+	for batch := 1; batch <= 10; batch++ {
+		sequence = append(sequence, GetStart(start, end, params, batch))
+	}
+	for batch := 1; batch <= 10; batch++ {
+		if result := GetStart(start, end, params, batch); result.Cmp(sequence[batch-1]) != 0 {
+			t.Errorf("expected %v, got %v", sequence[batch-1], result)
+		}
+	}
+
+	params.Seed++
+	same := 0
+	for batch := 1; batch <= 10; batch++ {
+		if GetStart(start, end, params, batch).Cmp(sequence[batch-1]) == 0 {
+			same++
+		}
+	}
+	if same == 10 {
+		t.Errorf("expected another seed to give another sequence")
+	}
+}
+
+func TestAppendRunLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.log")
+	params := domain.Parameters{TargetWallet: 20, Rng: true, Seed: 12345, BatchSize: 100, BatchCount: 3}
+	for i := 0; i < 2; i++ {
+		if !AppendRunLog(path, NewRunRecord(big.NewInt(0x80000), big.NewInt(0xfffff), params)) {
+			t.Fatalf("expected the record to be appended")
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); lines++ {
+		var record RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if record.Seed != 12345 || record.Min != "80000" || record.Max != "fffff" || record.BatchCount != 3 {
+			t.Errorf("expected the parameters of the run, got %+v", record)
+		}
+	}
+	if lines != 2 {
+		t.Errorf("expected 2, got %d", lines)
+	}
+}
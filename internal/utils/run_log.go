@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"encoding/json"
+	"log"
+	"math/big"
+	"os"
+	"time"
+)
+
+// RunRecord describes a random run, so the regions it covered can be regenerated from its seed.
+//
+// Fields:
+// - Time: The time the run started.
+// - TargetWallet: Index of the target wallet.
+// - Min: The smallest key of the wallet range, in hex.
+// - Max: The largest key of the wallet range, in hex.
+// - Seed: The seed of the random start locations.
+// - BatchSize: Size of each batch.
+// - BatchCount: Number of batches.
+type RunRecord struct {
+	Time         time.Time `json:"Time"`
+	TargetWallet int       `json:"TargetWallet"`
+	Min          string    `json:"Min"`
+	Max          string    `json:"Max"`
+	Seed         uint64    `json:"Seed"`
+	BatchSize    int64     `json:"BatchSize"`
+	BatchCount   int       `json:"BatchCount"`
+}
+
+// NewRunRecord creates the RunRecord of a run starting now.
+//
+// Parameters:
+// - start: The smallest key of the wallet range.
+// - end: The largest key of the wallet range.
+// - params: The domain.Parameters of the run.
+//
+// Returns:
+// - RunRecord: The record of the run.
+func NewRunRecord(start, end *big.Int, params domain.Parameters) RunRecord {
+	return RunRecord{
+		Time:         time.Now().UTC().Truncate(time.Second),
+		TargetWallet: params.TargetWallet,
+		Min:          start.Text(16),
+		Max:          end.Text(16),
+		Seed:         params.Seed,
+		BatchSize:    params.BatchSize,
+		BatchCount:   params.BatchCount,
+	}
+}
+
+// AppendRunLog appends a RunRecord as one JSON line to a run log file, creating the file if needed.
+//
+// Parameters:
+// - filePath: A string representing the path of the run log.
+// - record: The RunRecord to append.
+//
+// Returns:
+// - bool: True if the record was appended successfully, false otherwise.
+func AppendRunLog(filePath string, record RunRecord) bool {
+	jsonData, err := json.Marshal(record)
+	if err != nil {
+		log.Println("Error on Marshal function:", err)
+		return false
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Println("Error on open run log:", err)
+		return false
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(jsonData, '\n')); err != nil {
+		log.Println("Error on write run log:", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// tapTweakTag holds SHA-256("TapTweak"), the tag of the BIP-341 tweak hash.
+var tapTweakTag = sha256.Sum256([]byte("TapTweak"))
+
+// TaprootOutputKey computes the BIP-341 key-path output key of the internal public point (x, y) into out.
+//
+// The internal point P is first lifted to its even-y form, then tweaked without a script tree:
+// Q = P + int(hash_TapTweak(x(P)))G. Only the x coordinate of Q is written, since Taproot outputs commit to
+// x-only keys. The tweak requires a full scalar multiplication, so this costs far more than hashing a key.
+// Both coordinates must be normalized.
+//
+// Parameters:
+// - x: A pointer to the normalized x coordinate of the internal point.
+// - y: A pointer to the normalized y coordinate of the internal point.
+// - out: A pointer to the 32-byte array that receives the x-only output key.
+//
+// Returns:
+// - bool: False if the tweak is not a valid scalar or the output key is the point at infinity.
+func TaprootOutputKey(x, y *secp256k1.FieldVal, out *[domain.XOnlyKeySize]byte) bool {
+	var message [2*sha256.Size + domain.XOnlyKeySize]byte
+	copy(message[:], tapTweakTag[:])
+	copy(message[sha256.Size:], tapTweakTag[:])
+	x.PutBytesUnchecked(message[2*sha256.Size:])
+	tweakHash := sha256.Sum256(message[:])
+
+	var tweak secp256k1.ModNScalar
+	if tweak.SetBytes(&tweakHash) != 0 {
+		return false
+	}
+
+	var internal, tweakPoint, output secp256k1.JacobianPoint
+	internal.X.Set(x)
+	internal.Y.Set(y)
+	if y.IsOdd() {
+		internal.Y.Negate(1).Normalize()
+	}
+	internal.Z.SetInt(1)
+	secp256k1.ScalarBaseMultNonConst(&tweak, &tweakPoint)
+	secp256k1.AddNonConst(&internal, &tweakPoint, &output)
+	if (output.X.IsZero() && output.Y.IsZero()) || output.Z.IsZero() {
+		return false
+	}
+	output.ToAffine()
+	output.X.PutBytesUnchecked(out[:])
+	return true
+}
+
+// CreateTaprootOutputKey generates the BIP-341 key-path x-only output key from a given private key.
+//
+// Parameters:
+// - privKeyInt: A pointer to a big.Int representing the private key.
+//
+// Returns:
+// - []byte: The 32-byte x-only output key, or nil if the tweak is invalid.
+func CreateTaprootOutputKey(privKeyInt *big.Int) []byte {
+	var point secp256k1.JacobianPoint
+	secp256k1.PrivKeyFromBytes(privKeyInt.Bytes()).PubKey().AsJacobian(&point)
+	var out [domain.XOnlyKeySize]byte
+	if !TaprootOutputKey(&point.X, &point.Y, &out) {
+		return nil
+	}
+	return out[:]
+}
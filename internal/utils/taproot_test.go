@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/domain"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// taprootOutputKeyOf tweaks the even-y point with the given hex x-only internal key.
+func taprootOutputKeyOf(t *testing.T, internalKey string) string {
+	t.Helper()
+	raw, err := hex.DecodeString("02" + internalKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := secp256k1.ParsePubKey(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var point secp256k1.JacobianPoint
+	pubKey.AsJacobian(&point)
+	var out [domain.XOnlyKeySize]byte
+	if !TaprootOutputKey(&point.X, &point.Y, &out) {
+		t.Fatalf("%s: unexpected invalid tweak", internalKey)
+	}
+	return hex.EncodeToString(out[:])
+}
+
+func TestTaprootOutputKey_Vectors(t *testing.T) {
+	// Key-path outputs without a script tree, from the BIP-341 wallet test vectors and BIP-86.
+	tests := []struct {
+		internal, output string
+	}{
+		{
+			"d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			"53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		},
+		{
+			"cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			"a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+		},
+	}
+	for _, test := range tests {
+		if result := taprootOutputKeyOf(t, test.internal); result != test.output {
+			t.Errorf("expected %s, got %s", test.output, result)
+		}
+	}
+}
+
+func TestTaprootOutputKey_OddY(t *testing.T) {
+	// A key and its negation share the same x coordinate and must produce the same output key.
+	key := big.NewInt(1234567)
+	negated := new(big.Int).Sub(secp256k1.S256().N, key)
+	expected, result := CreateTaprootOutputKey(key), CreateTaprootOutputKey(negated)
+	if hex.EncodeToString(expected) != hex.EncodeToString(result) {
+		t.Errorf("expected %x, got %x", expected, result)
+	}
+}
+
+func TestDecodeAddress_Taproot(t *testing.T) {
+	// The BIP-86 address of the second test vector above.
+	hash, addressType, err := DecodeAddress("bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"
+	if addressType.String() != "p2tr" || hex.EncodeToString(hash) != expected {
+		t.Errorf("expected (p2tr, %s), got (%v, %x)", expected, addressType, hash)
+	}
+}
@@ -30,7 +30,8 @@ func GetWalletStartAndEnd(ranges domain.Ranges, params domain.Parameters) (*big.
 }
 
 // GetStart calculates the start value for the current batch based on the given parameters.
-// If RNG is enabled, it generates a random start value. If batch size is defined, it calculates the start based on the batch counter.
+// If RNG is enabled, it draws the start value of the batch from the seeded generator, so the same seed always yields
+// the same sequence of starts. If batch size is defined, it calculates the start based on the batch counter.
 //
 // Parameters:
 // - start: The initial start value as a *big.Int.
@@ -42,7 +43,7 @@ func GetWalletStartAndEnd(ranges domain.Ranges, params domain.Parameters) (*big.
 // - *big.Int: The calculated start value for the current batch.
 func GetStart(start, end *big.Int, params domain.Parameters, batchCounter int) *big.Int {
 	if params.Rng {
-		start, _ = GenerateRandomNumber(start, end)
+		start, _ = SeededRandomNumber(params.Seed, uint64(batchCounter), start, end)
 	} else if params.BatchSize != -1 {
 		startAdd := new(big.Int).Mul(
 			big.NewInt(params.BatchSize),
@@ -52,6 +53,42 @@ func GetStart(start, end *big.Int, params domain.Parameters, batchCounter int) *
 	return start
 }
 
+// PlaceInGap places a batch inside an uncovered gap of the progress. The batch holds BatchSize keys, or the whole gap
+// if it is smaller or the batch size is -1. With CenterGap it sits in the middle of the gap; with RandomGap its
+// start is drawn from the seeded generator, so the same seed always places the same batches. When the progress is a
+// bitmap, the offset is rounded down to a whole block, so a batch of whole blocks marks every block it searches.
+//
+// Parameters:
+// - gap: The uncovered Interval to place the batch in.
+// - params: The domain.Parameters structure containing parameters including batch size, gap mode and seed.
+// - batchCounter: The current batch counter.
+//
+// Returns:
+// - *big.Int: The first key of the batch.
+// - *big.Int: The last key of the batch.
+func PlaceInGap(gap collision.Interval, params domain.Parameters, batchCounter int) (*big.Int, *big.Int) {
+	first, last := gap.Get()
+	length := new(big.Int).Sub(last, first)
+	length.Add(length, big.NewInt(1))
+	size := length
+	if params.BatchSize != -1 {
+		size = MinBigInt(big.NewInt(params.BatchSize), length)
+	}
+
+	// The batch can start anywhere in the first length-size+1 keys of the gap.
+	slack := new(big.Int).Sub(length, size)
+	offset := new(big.Int).Rsh(slack, 1)
+	if params.GapMode == domain.RandomGap {
+		offset, _ = SeededRandomNumber(params.Seed, uint64(batchCounter), new(big.Int), slack)
+	}
+	if params.BitmapBlockSize > 0 {
+		offset.Sub(offset, new(big.Int).Mod(offset, big.NewInt(params.BitmapBlockSize)))
+	}
+	start := offset.Add(offset, first)
+	end := new(big.Int).Add(start, size)
+	return start, end.Sub(end, big.NewInt(1))
+}
+
 // GetEnd calculates the end value for the current batch based on the given parameters.
 // It adjusts the end value if a batch size is defined.
 //
@@ -77,12 +114,12 @@ func GetEnd(start, end *big.Int, params domain.Parameters) *big.Int {
 // - start: The start value of the current batch as a *big.Int.
 // - end: The end value of the current batch as a *big.Int.
 // - params: The domain.Parameters structure containing parameters including batch size.
-// - intervals: The collision.IntervalArray structure containing existing intervals.
+// - intervals: The collision.Store containing the searched keys.
 //
 // Returns:
 // - bool: True if a collision was resolved, false otherwise.
 // - collision.Interval: The adjusted interval after handling collisions.
-func HandleCollisions(startOriginal, start, end *big.Int, params domain.Parameters, intervals *collision.IntervalArray) (bool, collision.Interval) {
+func HandleCollisions(startOriginal, start, end *big.Int, params domain.Parameters, intervals collision.Store) (bool, collision.Interval) {
 	end = GetEnd(start, end, params)
 	interval := new(collision.Interval).Set(start, end)
 	hasCollision, newInterval := intervals.HandleIntervalCollision(*interval)
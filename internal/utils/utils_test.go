@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"GoKeyHunt/internal/collision"
+	"GoKeyHunt/internal/domain"
+	"math/big"
+	"testing"
+)
+
+func TestPlaceInGap_Center(t *testing.T) {
+	gap := *new(collision.Interval).SetInt(1000, 1999)
+	params := domain.Parameters{BatchSize: 100, GapMode: domain.CenterGap}
+	if start, end := PlaceInGap(gap, params, 1); start.Int64() != 1450 || end.Int64() != 1549 {
+		t.Errorf("expected [1450, 1549], got [%v, %v]", start, end)
+	}
+
+	params.BatchSize = 5000
+	if start, end := PlaceInGap(gap, params, 1); start.Int64() != 1000 || end.Int64() != 1999 {
+		t.Errorf("expected the whole gap, got [%v, %v]", start, end)
+	}
+	params.BatchSize = -1
+	if start, end := PlaceInGap(gap, params, 1); start.Int64() != 1000 || end.Int64() != 1999 {
+		t.Errorf("expected the whole gap, got [%v, %v]", start, end)
+	}
+
+	params = domain.Parameters{BatchSize: 100, GapMode: domain.CenterGap, BitmapBlockSize: 100}
+	if start, end := PlaceInGap(gap, params, 1); start.Int64() != 1400 || end.Int64() != 1499 {
+		t.Errorf("expected [1400, 1499], got [%v, %v]", start, end)
+	}
+}
+
+func TestPlaceInGap_RandomIsSeeded(t *testing.T) {
+	gap := *new(collision.Interval).SetInt(1000, 1999)
+	params := domain.Parameters{BatchSize: 100, GapMode: domain.RandomGap, Seed: 42}
+	starts := make(map[int64]bool)
+	for batch := 1; batch <= 20; batch++ {
+		start, end := PlaceInGap(gap, params, batch)
+		if start.Int64() < 1000 || end.Int64() > 1999 || new(big.Int).Sub(end, start).Int64() != 99 {
+			t.Fatalf("expected 100 keys inside the gap, got [%v, %v]", start, end)
+		}
+		if again, _ := PlaceInGap(gap, params, batch); again.Cmp(start) != 0 {
+			t.Errorf("expected %v, got %v", start, again)
+		}
+		starts[start.Int64()] = true
+	}
+	if len(starts) < 10 {
+		t.Errorf("expected spread out starts, got %v", starts)
+	}
+}